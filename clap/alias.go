@@ -0,0 +1,159 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Named args and sub-commands can have aliases, which are alternate names
+// accepted on the command line, and can be hidden from help, completions and
+// generated documentation. They can also be marked deprecated, in which case
+// they continue to work but a warning is written when they are used on the
+// command line. Deprecation applies to an argument as a whole, whichever of
+// its names is used. Hence, to rename an argument such that the old name
+// warns, register the old name as a separate hidden and deprecated argument
+// with the same destination instead of as an alias:
+//
+//     cmd.AddStringArg("log-file", "", &logFile, "", false, "Log file.")
+//     cmd.AddStringArg("logfile", "", &logFile, "", false, "Log file.").
+//         SetHidden().SetDeprecated("Use --log-file instead.")
+
+// SetAliases registers |aliases| as alternate long names of |namedArg|.
+func (namedArg *NamedArg) SetAliases(aliases ...string) *NamedArg {
+	for _, alias := range aliases {
+		namedArg.aliases = append(namedArg.aliases, alias)
+		namedArg.owner.registerArgName(alias, namedArg)
+	}
+
+	return namedArg
+}
+
+// SetHidden hides |namedArg| from help, completions and generated
+// documentation. It is still accepted on the command line.
+func (namedArg *NamedArg) SetHidden() *NamedArg {
+	namedArg.hidden = true
+	return namedArg
+}
+
+// SetDeprecated marks |namedArg| as deprecated. |hint|, which can be empty,
+// is appended to the warning written when the argument is specified on the
+// command line, and should name the replacement of the argument.
+func (namedArg *NamedArg) SetDeprecated(hint string) *NamedArg {
+	namedArg.deprecated = true
+	namedArg.deprecationHint = hint
+	return namedArg
+}
+
+// hasName returns true if |name| is the long name or an alias of |namedArg|.
+func (namedArg *NamedArg) hasName(name string) bool {
+	if namedArg.name == name {
+		return true
+	}
+
+	for _, alias := range namedArg.aliases {
+		if alias == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetAliases registers |aliases| as alternate names of |cmd| when it is used
+// as a sub-command. Aliases set before the command is added as a sub-command
+// are checked for conflicts by AddSubCmd. Conflicts of aliases set later are
+// reported by Validate, Parse and ParseArgs.
+func (cmd *Cmd) SetAliases(aliases ...string) *Cmd {
+	cmd.aliases = append(cmd.aliases, aliases...)
+	if cmd.parent != nil {
+		for _, alias := range aliases {
+			existing, exists := cmd.parent.subCmds[alias]
+			if exists {
+				cmd.parent.defErrors = append(cmd.parent.defErrors, fmt.Errorf(
+					"Alias '%s' of sub-command '%s' of command '%s' is already used by " +
+					"sub-command '%s'.", alias, cmd.name, cmd.parent.name, existing.name))
+				continue
+			}
+			cmd.parent.subCmds[alias] = cmd
+		}
+	}
+
+	return cmd
+}
+
+// SetHidden hides |cmd| from the help, completions and generated
+// documentation of its parent command. It can still be used on the command
+// line.
+func (cmd *Cmd) SetHidden() *Cmd {
+	cmd.hidden = true
+	return cmd
+}
+
+// SetDeprecated marks |cmd| as deprecated. |hint|, which can be empty, is
+// appended to the warning written when the command is used on the command
+// line.
+func (cmd *Cmd) SetDeprecated(hint string) *Cmd {
+	cmd.deprecated = true
+	cmd.deprecationHint = hint
+	return cmd
+}
+
+// SetWarningWriter sets the writer to which warnings about the use of
+// deprecated arguments and sub-commands are written while parsing. It
+// applies to |cmd| and those of its sub-commands which do not set a writer
+// of their own. If no writer is set, warnings are written to standard error.
+func (cmd *Cmd) SetWarningWriter(w io.Writer) *Cmd {
+	cmd.warningWriter = w
+	return cmd
+}
+
+func (cmd *Cmd) warningOutput() io.Writer {
+	for c := cmd; c != nil; c = c.parent {
+		if c.warningWriter != nil {
+			return c.warningWriter
+		}
+	}
+
+	return os.Stderr
+}
+
+func (cmd *Cmd) warnDeprecated(kind, name, hint string) {
+	if len(hint) > 0 {
+		hint = " " + hint
+	}
+
+	fmt.Fprintf(cmd.warningOutput(), "Warning: %s '%s' is deprecated.%s\n", kind, name, hint)
+}
+
+// visibleNamedArgs returns the named args of |cmd| which are not hidden.
+func (cmd *Cmd) visibleNamedArgs() []*NamedArg {
+	return visibleArgs(cmd.namedArgList)
+}
+
+func visibleArgs(args []*NamedArg) []*NamedArg {
+	var visible []*NamedArg
+	for _, arg := range args {
+		if !arg.hidden {
+			visible = append(visible, arg)
+		}
+	}
+
+	return visible
+}
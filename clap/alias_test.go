@@ -0,0 +1,159 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestArgAliases(t *testing.T) {
+	var logFile string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddStringArg("log-file", "", &logFile, "", false, "Log file.").SetAliases("logfile", "log")
+
+	_, err := cmd.Parse([]string{"--logfile", "a.log"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if logFile != "a.log" {
+		t.Errorf("Argument 'log-file' has value '%s'; expecting '%s'.", logFile, "a.log")
+	}
+	if !cmd.IsSet("log-file") || !cmd.IsSet("log") {
+		t.Errorf("Argument 'log-file' not reported as set by its name and alias.")
+	}
+
+	cmd.Clear()
+	cmd.EnableGNUMode()
+	_, err = cmd.Parse([]string{"--log=b.log"})
+	if err != nil {
+		t.Errorf("Error parsing in GNU mode.\n%s", err.Error())
+		return
+	}
+	if logFile != "b.log" {
+		t.Errorf("Argument 'log-file' has value '%s'; expecting '%s'.", logFile, "b.log")
+	}
+}
+
+func TestSubCmdAliases(t *testing.T) {
+	cmd := NewCmd("tool", "A tool.")
+	remove := NewCmd("remove", "Remove.").SetAliases("rm")
+	err := cmd.AddSubCmd(remove)
+	if err != nil {
+		t.Errorf("Error adding sub-command.\n%s", err.Error())
+		return
+	}
+
+	cmdList, err := cmd.Parse([]string{"rm"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if len(cmdList) != 2 || cmdList[1] != "remove" {
+		t.Errorf("Processed commands are '%s'; expecting [tool remove].", cmdList)
+	}
+
+	err = cmd.AddSubCmd(NewCmd("rm", "Another rm."))
+	if err == nil {
+		t.Errorf("Expecting an error adding a sub-command named like an alias.")
+	}
+
+	names := cmd.sortedSubCmdNames()
+	if len(names) != 1 || names[0] != "remove" {
+		t.Errorf("Sub-command names are '%s'; expecting [remove].", names)
+	}
+}
+
+func TestHidden(t *testing.T) {
+	var debug, verbose bool
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("debug", "", &debug, false, false, "Debug.").SetHidden()
+	cmd.AddBoolArg("verbose", "", &verbose, false, false, "Verbose.")
+	cmd.AddSubCmd(NewCmd("internal", "Internal.").SetHidden())
+	cmd.AddSubCmd(NewCmd("public", "Public."))
+
+	candidates := cmd.Complete([]string{"--"})
+	for _, candidate := range candidates {
+		if candidate == "--debug" {
+			t.Errorf("Hidden argument 'debug' offered as a completion.")
+		}
+	}
+	candidates = cmd.Complete([]string{""})
+	if len(candidates) != 1 || candidates[0] != "public" {
+		t.Errorf("Sub-command completions are '%s'; expecting [public].", candidates)
+	}
+
+	var buf bytes.Buffer
+	cmd.GenMarkdown(&buf)
+	if strings.Contains(buf.String(), "debug") || strings.Contains(buf.String(), "internal") {
+		t.Errorf("Hidden argument or sub-command in generated Markdown:\n%s", buf.String())
+	}
+
+	cmdList, err := cmd.Parse([]string{"internal"})
+	if err != nil || len(cmdList) != 2 {
+		t.Errorf("Hidden sub-command 'internal' not accepted.")
+	}
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--debug"})
+	if err != nil || !debug {
+		t.Errorf("Hidden argument 'debug' not accepted.")
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--debg"})
+	if err == nil || strings.Contains(err.Error(), "debug") {
+		t.Errorf("Hidden argument 'debug' suggested for 'debg'; got '%v'.", err)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	var logFile string
+	var warnings bytes.Buffer
+	cmd := NewCmd("tool", "A tool.")
+	cmd.SetWarningWriter(&warnings)
+	cmd.AddStringArg("log-file", "", &logFile, "", false, "Log file.")
+	cmd.AddStringArg("logfile", "", &logFile, "", false, "Log file.").
+		SetHidden().SetDeprecated("Use --log-file instead.")
+	cmd.AddSubCmd(NewCmd("sync", "Sync.").SetDeprecated(""))
+
+	_, err := cmd.Parse([]string{"--logfile", "a.log"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if logFile != "a.log" {
+		t.Errorf("Argument 'logfile' has value '%s'; expecting '%s'.", logFile, "a.log")
+	}
+	expected := "Warning: Argument 'logfile' is deprecated. Use --log-file instead.\n"
+	if warnings.String() != expected {
+		t.Errorf("Warnings are '%s'; expecting '%s'.", warnings.String(), expected)
+	}
+
+	warnings.Reset()
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"sync"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	expected = "Warning: Command 'sync' is deprecated.\n"
+	if warnings.String() != expected {
+		t.Errorf("Warnings are '%s'; expecting '%s'.", warnings.String(), expected)
+	}
+}
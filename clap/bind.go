@@ -0,0 +1,280 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Struct tags understood by Bind.
+//
+// A field is bound only if it has a 'name' tag. For fields of struct type
+// which do not implement Value, the 'name' tag is the name of a sub-command
+// and the 'help' tag is its description. For all other fields, the tags
+// describe a named argument:
+//
+//     name     - The long name of the argument.
+//     short    - The short name of the argument.
+//     default  - The default value of the argument. If not specified, the
+//                value held by the field at the time of binding is used.
+//                Defaults of slice fields are comma separated.
+//     required - "true" if the argument is required.
+//     help     - The help text of the argument.
+//     choices  - Comma separated list of values allowed for a string field.
+//     env      - The environment variable of the argument.
+//
+// Untagged anonymous struct fields are flattened into the enclosing command.
+const (
+	tagName = "name"
+	tagShort = "short"
+	tagDefault = "default"
+	tagRequired = "required"
+	tagHelp = "help"
+	tagChoices = "choices"
+	tagEnv = "env"
+)
+
+// NewCmdFromStruct creates a new command with name |name| and description
+// |description|, and binds the struct pointed to by |v| to it.
+func NewCmdFromStruct(name, description string, v interface{}) (*Cmd, error) {
+	cmd := NewCmd(name, description)
+	err := cmd.Bind(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+// Bind registers a named argument with |cmd| for every tagged field of the
+// struct pointed to by |v|, with the field as the argument's destination.
+// Tagged fields of struct type are bound to new sub-commands of |cmd|.
+func (cmd *Cmd) Bind(v interface{}) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf(
+			"Cannot bind command '%s' to a value of type '%T'; expecting a " +
+			"pointer to a struct.", cmd.name, v)
+	}
+
+	return cmd.bindStruct(ptr.Elem())
+}
+
+func (cmd *Cmd) bindStruct(structVal reflect.Value) error {
+	structType := structVal.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldVal := structVal.Field(i)
+
+		name, tagged := field.Tag.Lookup(tagName)
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				err := cmd.bindStruct(fieldVal)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			return fmt.Errorf(
+				"Cannot bind unexported field '%s' of '%s' to command '%s'.",
+				field.Name, structType.Name(), cmd.name)
+		}
+
+		var err error
+		if isSubCmdField(field) {
+			err = cmd.bindSubCmd(name, field, fieldVal)
+		} else {
+			err = cmd.bindField(name, field, fieldVal)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+
+func isSubCmdField(field reflect.StructField) bool {
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct &&
+		!reflect.PtrTo(fieldType).Implements(valueType)
+}
+
+func (cmd *Cmd) bindSubCmd(
+	name string, field reflect.StructField, fieldVal reflect.Value) error {
+	if fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(field.Type.Elem()))
+		}
+		fieldVal = fieldVal.Elem()
+	}
+
+	subCmd := NewCmd(name, field.Tag.Get(tagHelp))
+	err := subCmd.bindStruct(fieldVal)
+	if err != nil {
+		return err
+	}
+
+	return cmd.AddSubCmd(subCmd)
+}
+
+func (cmd *Cmd) bindField(
+	name string, field reflect.StructField, fieldVal reflect.Value) error {
+	short := field.Tag.Get(tagShort)
+	help := field.Tag.Get(tagHelp)
+
+	required := false
+	requiredStr, exists := field.Tag.Lookup(tagRequired)
+	if exists {
+		var err error
+		required, err = strconv.ParseBool(requiredStr)
+		if err != nil {
+			return fmt.Errorf(
+				"Invalid '%s' tag on field '%s'.\n%s",
+				tagRequired, field.Name, err.Error())
+		}
+	}
+
+	defValStr, hasDef := field.Tag.Lookup(tagDefault)
+
+	target := fieldVal.Addr().Interface()
+	if fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Implements(valueType) {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(field.Type.Elem()))
+		}
+		target = fieldVal.Interface()
+	}
+
+	var arg *NamedArg
+	var err error
+	switch dest := target.(type) {
+	case Value:
+		if hasDef {
+			err = dest.Set(defValStr)
+		}
+		if err == nil {
+			arg = cmd.AddValueArg(name, short, dest, required, help)
+		}
+	case *int:
+		def := *dest
+		if hasDef {
+			var int64Val int64
+			int64Val, err = strconv.ParseInt(defValStr, 0, 0)
+			def = int(int64Val)
+		}
+		if err == nil {
+			arg = cmd.AddIntArg(name, short, dest, def, required, help)
+		}
+	case *int64:
+		def := *dest
+		if hasDef {
+			def, err = strconv.ParseInt(defValStr, 0, 64)
+		}
+		if err == nil {
+			arg = cmd.AddInt64Arg(name, short, dest, def, required, help)
+		}
+	case *uint:
+		def := *dest
+		if hasDef {
+			var uint64Val uint64
+			uint64Val, err = strconv.ParseUint(defValStr, 0, 0)
+			def = uint(uint64Val)
+		}
+		if err == nil {
+			arg = cmd.AddUIntArg(name, short, dest, def, required, help)
+		}
+	case *uint64:
+		def := *dest
+		if hasDef {
+			def, err = strconv.ParseUint(defValStr, 0, 64)
+		}
+		if err == nil {
+			arg = cmd.AddUInt64Arg(name, short, dest, def, required, help)
+		}
+	case *float64:
+		def := *dest
+		if hasDef {
+			def, err = strconv.ParseFloat(defValStr, 64)
+		}
+		if err == nil {
+			arg = cmd.AddFloat64Arg(name, short, dest, def, required, help)
+		}
+	case *bool:
+		def := *dest
+		if hasDef {
+			def, err = strconv.ParseBool(defValStr)
+		}
+		if err == nil {
+			arg = cmd.AddBoolArg(name, short, dest, def, required, help)
+		}
+	case *string:
+		def := *dest
+		if hasDef {
+			def = defValStr
+		}
+		choicesStr, hasChoices := field.Tag.Lookup(tagChoices)
+		if hasChoices {
+			choices := splitSliceValue(choicesStr)
+			arg = cmd.AddEnumArg(name, short, dest, def, choices, required, help)
+		} else {
+			arg = cmd.AddStringArg(name, short, dest, def, required, help)
+		}
+	case *time.Duration:
+		def := *dest
+		if hasDef {
+			def, err = time.ParseDuration(defValStr)
+		}
+		if err == nil {
+			arg = cmd.AddDurationArg(name, short, dest, def, required, help)
+		}
+	case *[]string, *[]int, *[]int64, *[]uint, *[]uint64, *[]float64:
+		if hasDef {
+			err = setSliceDest(dest, defValStr, true)
+		}
+		if err == nil {
+			arg = cmd.addSliceArg(name, short, help, dest, required)
+		}
+	default:
+		return fmt.Errorf(
+			"Cannot bind field '%s' of unsupported type '%s' to command '%s'.",
+			field.Name, field.Type, cmd.name)
+	}
+
+	if err != nil {
+		return fmt.Errorf(
+			"Invalid default value for field '%s'.\n%s", field.Name, err.Error())
+	}
+
+	envVar, hasEnv := field.Tag.Lookup(tagEnv)
+	if hasEnv {
+		arg.SetEnv(envVar)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"testing"
+)
+
+type commonOptions struct {
+	Verbose bool `name:"verbose" short:"v" help:"Print more output."`
+}
+
+type fetchOptions struct {
+	URL string `name:"url" required:"true" help:"URL to fetch."`
+	Retries uint `name:"retries" default:"3" help:"Number of retries."`
+}
+
+type serverOptions struct {
+	commonOptions
+	Port int `name:"port" short:"p" default:"8080" help:"Port to listen on."`
+	Ratio float64 `name:"ratio" help:"Sampling ratio."`
+	Name string `name:"name" short:"n" required:"true" help:"Server name."`
+	internal int
+	Fetch fetchOptions `name:"fetch" help:"Fetch a URL."`
+}
+
+func TestBind(t *testing.T) {
+	opts := new(serverOptions)
+	opts.Ratio = 0.5
+	cmd, err := NewCmdFromStruct("server", "A test server.", opts)
+	if err != nil {
+		t.Errorf("Error while binding:\n%s", err.Error())
+		return
+	}
+
+	if opts.Port != 8080 {
+		t.Errorf("Argument 'port' has value '%d'; expecting '%d'.", opts.Port, 8080)
+	}
+
+	_, err = cmd.Parse([]string{"-n", "test", "--verbose"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if opts.Name != "test" {
+		t.Errorf("Argument 'name' has value '%s'; expecting '%s'.", opts.Name, "test")
+	}
+	if opts.Ratio != 0.5 {
+		t.Errorf("Argument 'ratio' has value '%f'; expecting '%f'.", opts.Ratio, 0.5)
+	}
+	if !opts.Verbose {
+		t.Errorf("Argument 'verbose' of embedded struct not set.")
+	}
+
+	err = cmd.Clear()
+	if err != nil {
+		t.Errorf("Error clearing arg set.\n%s", err.Error())
+		return
+	}
+
+	_, err = cmd.Parse([]string{"-p", "10"})
+	if err == nil {
+		t.Errorf("Expecting an error for missing required argument 'name'.")
+	}
+}
+
+func TestBindSubCmd(t *testing.T) {
+	opts := new(serverOptions)
+	cmd, err := NewCmdFromStruct("server", "A test server.", opts)
+	if err != nil {
+		t.Errorf("Error while binding:\n%s", err.Error())
+		return
+	}
+
+	cmdList, err := cmd.Parse([]string{"-n", "test", "fetch", "-url=http://localhost"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if len(cmdList) != 2 || cmdList[1] != "fetch" {
+		t.Errorf("Expecting command list [server, fetch]. Found '%s'", cmdList)
+	}
+	if opts.Fetch.URL != "http://localhost" {
+		t.Errorf(
+			"Argument 'url' has value '%s'; expecting '%s'.",
+			opts.Fetch.URL, "http://localhost")
+	}
+	if opts.Fetch.Retries != 3 {
+		t.Errorf("Argument 'retries' has value '%d'; expecting '%d'.", opts.Fetch.Retries, 3)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	var notStruct int
+	_, err := NewCmdFromStruct("bad", "A bad command.", &notStruct)
+	if err == nil {
+		t.Errorf("Expecting an error binding a non-struct value.")
+	}
+
+	type badDefault struct {
+		Count int `name:"count" default:"many"`
+	}
+	_, err = NewCmdFromStruct("bad", "A bad command.", new(badDefault))
+	if err == nil {
+		t.Errorf("Expecting an error for an invalid default value.")
+	}
+
+	type badType struct {
+		Ch chan int `name:"ch"`
+	}
+	_, err = NewCmdFromStruct("bad", "A bad command.", new(badType))
+	if err == nil {
+		t.Errorf("Expecting an error for an unsupported field type.")
+	}
+}
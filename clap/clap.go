@@ -19,6 +19,8 @@ package clap
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -30,79 +32,66 @@ type NamedArg struct {
 	short string
 	help string
 	defValStr string
-	dest interface{}
+	value Value
 	required bool
-	set bool
+
+	// Where the current value of the argument came from, and for values
+	// from the environment or config files, the environment variable or
+	// the location in the config file.
+	source Source
+	origin string
+
+	// The environment variable from which the value of the argument is
+	// read if it is not specified on the command line.
+	env string
+
+	// Values of the argument loaded from config files.
+	config []configValue
+
+	// How shell completion scripts complete the value of the argument.
+	completionHint CompletionHint
+
+	// Computes the completion candidates of the value of the argument.
+	completeFunc CompleteFunc
+
+	// Indicates whether the argument is also accepted by all the
+	// sub-commands of the command it belongs to.
+	persistent bool
+
+	// The command the argument belongs to.
+	owner *Cmd
+
+	// Alternate long names of the argument.
+	aliases []string
+
+	// Indicates whether the argument is hidden from help.
+	hidden bool
+
+	// Indicates whether the argument is deprecated, and the hint written
+	// along with the warning when it is used.
+	deprecated bool
+	deprecationHint string
+
+	// The value of the argument when it is specified without a value, if
+	// the argument has an implicit value.
+	implicitValue string
+	hasImplicitValue bool
+
+	// The section of help in which the argument is listed.
+	helpGroup string
 }
 
 func (namedArg *NamedArg) Reset() error {
-	namedArg.set = false
-	if !namedArg.required {
-		var valid bool
-		var err error
-		switch namedArg.dest.(type) {
-		case *int:
-			var ptr *int
-			ptr, valid = namedArg.dest.(*int)
-			if valid {
-				var int64Val int64
-				int64Val, err = strconv.ParseInt(namedArg.defValStr, 0, 0)
-				if err == nil {
-					*ptr = int(int64Val)
-				}
-			}
-		case *uint:
-			var ptr *uint
-			ptr, valid = namedArg.dest.(*uint)
-			if valid {
-				var uint64Val uint64
-				uint64Val, err = strconv.ParseUint(namedArg.defValStr, 0, 0)
-				if err == nil {
-					*ptr = uint(uint64Val)
-				}
-			}
-		case *int64:
-			var ptr *int64
-			ptr, valid = namedArg.dest.(*int64)
-			if valid {
-				*ptr, err = strconv.ParseInt(namedArg.defValStr, 0, 64)
-			}
-		case *uint64:
-			var ptr *uint64
-			ptr, valid = namedArg.dest.(*uint64)
-			if valid {
-				*ptr, err = strconv.ParseUint(namedArg.defValStr, 0, 64)
-			}
-		case *float64:
-			var ptr *float64
-			ptr, valid = namedArg.dest.(*float64)
-			if valid {
-				*ptr, err = strconv.ParseFloat(namedArg.defValStr, 64)
-			}
-		case *bool:
-			var ptr *bool
-			ptr, valid = namedArg.dest.(*bool)
-			if valid {
-				*ptr, err = strconv.ParseBool(namedArg.defValStr)
-			}
-		case *string:
-			var ptr *string
-			ptr, valid = namedArg.dest.(*string)
-			if valid {
-				*ptr = namedArg.defValStr
-			}
-		default:
-			err := fmt.Errorf(
-				"Unexpected argument type while resetting named arg '%s'.",
-				namedArg.name)
-			return err
-		}
+	namedArg.setSource(SourceDefault, "")
 
-		if !valid {
-			err = fmt.Errorf(
-				"Unable to cast to argument type for arg '%s'.", namedArg.name)
-			return err
-		}
+	repeated, isRepeated := namedArg.value.(repeatedValue)
+	if isRepeated {
+		repeated.reset()
+		return nil
+	}
+
+	if !namedArg.required {
+		err := namedArg.value.Set(namedArg.defValStr)
 		if err != nil {
 			err = fmt.Errorf(
 				"Error while resetting named arg '%s' to default value.\n%s",
@@ -115,15 +104,25 @@ func (namedArg *NamedArg) Reset() error {
 	return nil
 }
 
-func newNamedArg(name, short, help, defValStr string, dest interface{}, required bool) *NamedArg {
+// SetPersistent marks |namedArg| as persistent. A persistent argument is
+// accepted anywhere in the sub-command chain of the command it belongs to,
+// both before and after the names of the sub-commands. The help of the
+// sub-commands lists it under "Global options".
+func (namedArg *NamedArg) SetPersistent() *NamedArg {
+	namedArg.persistent = true
+	return namedArg
+}
+
+func newNamedArg(name, short, help, defValStr string, value Value, required bool) *NamedArg {
 	arg := new(NamedArg)
 	arg.name = name
 	arg.short = short
 	arg.help = help
 	arg.defValStr = defValStr
-	arg.dest = dest
+	arg.value = value
 	arg.required = required
-	arg.set = false
+	arg.source = SourceDefault
+	arg.origin = ""
 
 	return arg
 }
@@ -135,6 +134,39 @@ type Cmd struct {
 	// Sub-commands
 	subCmds map[string]*Cmd
 
+	// The command this command is a sub-command of.
+	parent *Cmd
+
+	// Alternate names of the command when used as a sub-command.
+	aliases []string
+
+	// Indicates whether the command is hidden from the help of its parent.
+	hidden bool
+
+	// Indicates whether the command is deprecated, and the hint written
+	// along with the warning when it is used.
+	deprecated bool
+	deprecationHint string
+
+	// The writer to which warnings are written while parsing.
+	warningWriter io.Writer
+
+	// Example invocations of the command shown in its help.
+	examples []example
+
+	// The width to which help is wrapped.
+	helpWidth int
+
+	// Help topics of the command, and the one requested with the 'help'
+	// sub-command while parsing.
+	helpTopics []*helpTopic
+	requestedTopic *helpTopic
+
+	// Errors in the definition of the command found while registering
+	// arguments and sub-commands. They are reported by Validate, Parse and
+	// ParseArgs.
+	defErrors []error
+
 	// Command description
 	description string
 
@@ -149,12 +181,45 @@ type Cmd struct {
 	// This is populated while parsing.
 	argList []Arg
 
+	// Indices of the unnamed arguments in the arguments passed to Parse.
+	argIndices []int
+
+	// List of declared positional arguments.
+	positionals []*Positional
+
+	// Constraints between named args.
+	constraints []*argConstraint
+
 	// Indicates whether -h or --help was specified during parsing.
 	shouldRenderHelp bool
 
+	// The named arg for -h and --help.
+	helpArg *NamedArg
+
+	// Prefix of the environment variables of named args which do not
+	// specify an environment variable explicitly. Environment variables are
+	// not looked up for such named args if this is empty.
+	envPrefix string
+
 	// Indicates whether the Parse method was called and that it was
 	// successfull.
 	parsed bool
+
+	// Indicates whether arguments are parsed following GNU conventions.
+	gnuMode bool
+
+	// Indicates whether '@file' arguments are expanded to the contents of
+	// the file.
+	responseFiles bool
+
+	// Callbacks invoked by Execute.
+	run RunFunc
+	preRun RunFunc
+	postRun RunFunc
+
+	// The writers to which Execute writes its output and errors.
+	output io.Writer
+	errorOutput io.Writer
 }
 
 // NewCmd creates a new command with name |name|.
@@ -170,7 +235,7 @@ func NewCmd(name string, description string) *Cmd {
 	cmd.namedArgMap = make(map[string]*NamedArg)
 	cmd.subCmds = make(map[string]*Cmd)
 
-	cmd.AddBoolArg(
+	cmd.helpArg = cmd.AddBoolArg(
 		"help", "h", &cmd.shouldRenderHelp, cmd.shouldRenderHelp,
 		false, fmt.Sprintf("Print '%s' usage information.", name))
 
@@ -186,242 +251,587 @@ func (cmd *Cmd) Description() string {
 }
 
 func (cmd *Cmd) AddSubCmd(subCmd *Cmd) error {
-	subCmdName := subCmd.Name()
-	_, exists := cmd.subCmds[subCmdName]
-	if exists {
-		return fmt.Errorf(
-			"Sub-command with name '%s' already registered with '%s'.",
-			subCmdName, cmd.name)
+	subCmdNames := append([]string{subCmd.Name()}, subCmd.aliases...)
+	for _, subCmdName := range subCmdNames {
+		_, exists := cmd.subCmds[subCmdName]
+		if exists {
+			return fmt.Errorf(
+				"Sub-command with name '%s' already registered with '%s'.",
+				subCmdName, cmd.name)
+		}
 	}
 
-	cmd.subCmds[subCmdName] = subCmd
+	for _, subCmdName := range subCmdNames {
+		cmd.subCmds[subCmdName] = subCmd
+	}
+	subCmd.parent = cmd
 	return nil
 }
 
 func (cmd *Cmd) addNamedArg(
-	name, short, help, defValStr string, dest interface{}, required bool) {
-	arg := newNamedArg(name, short, help, defValStr, dest, required)
+	name, short, help, defValStr string, value Value, required bool) *NamedArg {
+	arg := newNamedArg(name, short, help, defValStr, value, required)
+	arg.owner = cmd
 	cmd.namedArgList = append(cmd.namedArgList, arg)
-	cmd.namedArgMap[name] = arg
-	cmd.namedArgMap[short] = arg
+	cmd.registerArgName(name, arg)
+	if len(short) > 0 {
+		cmd.registerArgName(short, arg)
+	}
+
+	return arg
 }
 
 func (cmd *Cmd) AddIntArg(
-	name string, short string, dest *int, def int, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), dest, required)
+	name string, short string, dest *int, def int, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), newIntValue(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddInt64Arg(
-	name string, short string, dest *int64, def int64, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), dest, required)
+	name string, short string, dest *int64, def int64, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), newInt64Value(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddUIntArg(
-	name string, short string, dest *uint, def uint, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), dest, required)
+	name string, short string, dest *uint, def uint, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), newUIntValue(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddUInt64Arg(
-	name string, short string, dest *uint64, def uint64, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), dest, required)
+	name string, short string, dest *uint64, def uint64, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%d", def), newUInt64Value(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddFloat64Arg(
-	name string, short string, dest *float64, def float64, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%f", def), dest, required)
+	name string, short string, dest *float64, def float64, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%f", def), newFloat64Value(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddBoolArg(
-	name string, short string, dest *bool, def bool, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%t", def), dest, required)
+	name string, short string, dest *bool, def bool, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%t", def), newBoolValue(dest), required)
 	*dest = def
+	return arg
 }
 
 func (cmd *Cmd) AddStringArg(
-	name string, short string, dest *string, def string, required bool, help string) {
-	cmd.addNamedArg(name, short, help, fmt.Sprintf("%s", def), dest, required)
+	name string, short string, dest *string, def string, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, fmt.Sprintf("%s", def), newStringValue(dest), required)
 	*dest = def
+	return arg
 }
 
-func (cmd *Cmd) Parse(arguments []string) ([]string, error) {
-	processedCmds := []string{cmd.name}
+// AddValueArg adds a named argument with a user defined value type. The
+// current value of |value|, as returned by its String method, is the
+// default value of the argument.
+func (cmd *Cmd) AddValueArg(
+	name string, short string, value Value, required bool, help string) *NamedArg {
+	return cmd.addNamedArg(name, short, help, value.String(), value, required)
+}
 
-	if len(arguments) > 0 {
-		subCmd, exists := cmd.subCmds[arguments[0]]
-		if exists {
-			subCmdList, err := subCmd.Parse(arguments[1:])
-			return append(processedCmds, subCmdList...), err
+// EnableGNUMode makes Parse follow the GNU conventions for command line
+// arguments when parsing the arguments of |cmd| and its sub-commands. See
+// parseGNUNamedArg for the details.
+func (cmd *Cmd) EnableGNUMode() {
+	cmd.gnuMode = true
+}
+
+// isGNUMode returns true if the arguments of |cmd| are parsed following the
+// GNU conventions, which is the case if GNU mode is enabled for |cmd| or any
+// of its ancestors.
+func (cmd *Cmd) isGNUMode() bool {
+	for c := cmd; c != nil; c = c.parent {
+		if c.gnuMode {
+			return true
 		}
 	}
 
+	return false
+}
+
+// parseContext holds the state of a call to Parse which is shared by all
+// the commands in the parsed command chain.
+type parseContext struct {
+	// The arguments passed to Parse.
+	arguments []string
+
+	// Names of the commands parsed so far, starting with the root command.
+	cmdPath []string
+
+	// The commands parsed so far, starting with the root command.
+	cmds []*Cmd
+
+	// Indicates whether arguments are parsed following GNU conventions.
+	gnuMode bool
+}
+
+// path returns a copy of the command path for use in errors.
+func (ctx *parseContext) path() []string {
+	return append([]string(nil), ctx.cmdPath...)
+}
+
+// newParseContext creates the context for parsing |arguments| with |cmd| as
+// the root command.
+func (cmd *Cmd) newParseContext(arguments []string) (*parseContext, error) {
+	if cmd.responseFiles {
+		var err error
+		arguments, err = cmd.expandResponseFiles(arguments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := new(parseContext)
+	ctx.arguments = arguments
+	ctx.gnuMode = false
+
+	return ctx, nil
+}
+
+func (cmd *Cmd) Parse(arguments []string) ([]string, error) {
+	err := cmd.checkDefinition()
+	if err != nil {
+		return []string{cmd.name}, err
+	}
+
+	ctx, err := cmd.newParseContext(arguments)
+	if err != nil {
+		return []string{cmd.name}, err
+	}
+
+	err = cmd.parse(ctx, 0)
+	return ctx.cmdPath, err
+}
+
+// parse parses the arguments from index |start| of |ctx.arguments| as the
+// arguments of |cmd|.
+func (cmd *Cmd) parse(ctx *parseContext, start int) error {
+	ctx.cmdPath = append(ctx.cmdPath, cmd.name)
+	ctx.cmds = append(ctx.cmds, cmd)
+	ctx.gnuMode = ctx.gnuMode || cmd.gnuMode
+	arguments := ctx.arguments
+
 	argCount := len(arguments)
-	for i := 0; i < argCount; i++ {
+	for i := start; i < argCount; i++ {
 		argument := arguments[i]
-		if strings.HasPrefix(argument, "-") {
-			// A named argument can be specified in the following ways:
-			//     -name value
-			//     --name value
-			//     -name=value
-			//     --name=value
-			// If it were a bool value argument, the value can be omitted to
-			// imply a value of 'true':
-			//     -name
-			//     --name
-
-			stripped := argument[1:]
-			if strings.HasPrefix(stripped, "-") {
-				stripped = stripped[1:]
+		var err error
+		if ctx.gnuMode && argument == "--" {
+			// All arguments after "--" are unnamed arguments.
+			for j := i + 1; j < argCount; j++ {
+				cmd.addUnnamedArg(arguments[j], j)
 			}
-
-			var arg *NamedArg
-			var valStr string
-
-			indexOfEqual := strings.Index(stripped, "=")
-			if indexOfEqual < 0 {
-				// The stripped argument is the name if there is no "=".
-				name := stripped
-				var exists bool
-				arg, exists = cmd.namedArgMap[name]
-				if !exists {
-					err := fmt.Errorf("Unknown argument '%s'.", name)
-					return processedCmds, err
-				}
-
-				// If the argument is of bool type, then the next argument
-				// can be a string which can be parsed error free by
-				// strconv.ParseBool, or can be unspecified to mean 'true'.
-				i += 1
-				switch arg.dest.(type)  {
-				default:
-					if i >= argCount {
-						err := fmt.Errorf(
-							"Missing value for argument '%s'.", name)
-						return processedCmds, err
-					}
-					valStr = arguments[i]
-				case *bool:
-					if i >= argCount {
-						i -= 1;
-						valStr = "true"
-					} else {
-						nextArgStr := arguments[i]
-						_, err := strconv.ParseBool(nextArgStr)
-						if err == nil {
-							valStr = nextArgStr
-						} else {
-							i -= 1
-							valStr = "true"
-						}
-					}
+			break
+		} else if ctx.gnuMode && cmd.isGNUNamedArg(argument) {
+			i, err = cmd.parseGNUNamedArg(ctx, i)
+		} else if !ctx.gnuMode && strings.HasPrefix(argument, "-") {
+			i, err = cmd.parseNamedArg(ctx, i)
+		} else if len(cmd.argList) == 0 && argument == helpCmdName &&
+			cmd.hasHelpCmd() && !cmd.shouldRenderHelp {
+			// The built-in help sub-command.
+			return cmd.parseHelpCmd(ctx, i + 1)
+		} else if len(cmd.argList) == 0 && len(cmd.subCmds) > 0 && !cmd.shouldRenderHelp {
+			// The first unnamed argument of a command with sub-commands
+			// names the sub-command which parses the rest of the arguments.
+			subCmd, exists := cmd.subCmds[argument]
+			if exists {
+				if subCmd.deprecated {
+					subCmd.warnDeprecated("Command", argument, subCmd.deprecationHint)
 				}
-			} else if indexOfEqual == 0 {
-				// This is an error
-				err := fmt.Errorf(
-					"Probably missing an argument name in '%s'.", argument)
-				return processedCmds, err
-			} else {
-				name := stripped[0:indexOfEqual]
-				valStr = stripped[indexOfEqual + 1:]
-				var exists bool
-				arg, exists = cmd.namedArgMap[name]
-				if !exists {
-					err := fmt.Errorf("Unknown argument '%s'.", name)
-					return processedCmds, err
+				return subCmd.parse(ctx, i + 1)
+			}
+			if len(cmd.positionals) == 0 {
+				// A word close to the name of a sub-command is most likely
+				// a mistyped sub-command. Other words are unnamed
+				// arguments.
+				unknownErr := cmd.unknownSubCmdError(ctx, argument, i)
+				if len(unknownErr.Suggestions) > 0 {
+					return unknownErr
 				}
 			}
+			cmd.addUnnamedArg(argument, i)
+		} else {
+			// This is not a named argument.
+			cmd.addUnnamedArg(argument, i)
+		}
+		if err != nil {
+			return err
+		}
+	}
 
-			var err error
-			var valid bool
-			switch arg.dest.(type) {
-			case *int:
-				var ptr *int
-				ptr, valid = arg.dest.(*int)
-				if valid {
-					var int64Val int64
-					int64Val, err = strconv.ParseInt(valStr, 0, 0)
-					if err == nil {
-						*ptr = int(int64Val)
-					}
-				}
-			case *uint:
-				var ptr *uint
-				ptr, valid = arg.dest.(*uint)
-				if valid {
-					var uint64Val uint64
-					uint64Val, err = strconv.ParseUint(valStr, 0, 0)
-					if err == nil {
-						*ptr = uint(uint64Val)
-					}
-				}
-			case *int64:
-				var ptr *int64
-				ptr, valid = arg.dest.(*int64)
-				if valid {
-					*ptr, err = strconv.ParseInt(valStr, 0, 64)
-				}
-			case *uint64:
-				var ptr *uint64
-				ptr, valid = arg.dest.(*uint64)
-				if valid {
-					*ptr, err = strconv.ParseUint(valStr, 0, 64)
-				}
-			case *float64:
-				var ptr *float64
-				ptr, valid = arg.dest.(*float64)
-				if valid {
-					*ptr, err = strconv.ParseFloat(valStr, 64)
-				}
-			case *bool:
-				var ptr *bool
-				ptr, valid = arg.dest.(*bool)
-				if valid {
-					*ptr, err = strconv.ParseBool(valStr)
+	if !cmd.shouldRenderHelp {
+		// Environment variables and config files are looked up only after
+		// the whole command line is parsed, so that values of repeatable
+		// arguments specified after a sub-command replace, rather than add
+		// to, the values from the environment or config files.
+		for _, c := range ctx.cmds {
+			err := c.applyEnv(ctx)
+			if err != nil {
+				return err
+			}
+
+			err = c.applyConfig(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The named args of all the commands in the chain can be specified
+		// after the sub-commands, so they are checked only now.
+		for _, c := range ctx.cmds {
+			for _, arg := range c.namedArgList {
+				if arg.required && !arg.isSet() {
+					return &MissingRequiredError{ctx.path(), arg.name, false}
 				}
-			case *string:
-				var ptr *string
-				ptr, valid = arg.dest.(*string)
-				if valid {
-					*ptr = valStr
+			}
+
+			err := c.checkConstraints(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := cmd.assignPositionals(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *Cmd) addUnnamedArg(argument string, index int) {
+	cmd.argList = append(cmd.argList, Arg(argument))
+	cmd.argIndices = append(cmd.argIndices, index)
+}
+
+// parseNamedArg parses the named argument at index |i| of |ctx.arguments|,
+// and returns the index of the last argument consumed.
+func (cmd *Cmd) parseNamedArg(ctx *parseContext, i int) (int, error) {
+	// A named argument can be specified in the following ways:
+	//     -name value
+	//     --name value
+	//     -name=value
+	//     --name=value
+	// If it were a bool value argument, the value can be omitted to
+	// imply a value of 'true':
+	//     -name
+	//     --name
+	arguments := ctx.arguments
+	argument := arguments[i]
+	stripped := argument[1:]
+	if strings.HasPrefix(stripped, "-") {
+		stripped = stripped[1:]
+	}
+
+	var arg *NamedArg
+	var valStr string
+
+	indexOfEqual := strings.Index(stripped, "=")
+	if indexOfEqual < 0 {
+		// The stripped argument is the name if there is no "=".
+		name := stripped
+		var exists bool
+		arg, exists = cmd.lookupArg(name)
+		negated, isNegated := cmd.lookupNegated(name)
+		if !exists && isNegated {
+			return i, cmd.setNamedArg(ctx, negated, "false", i)
+		}
+		if !exists {
+			return i, cmd.unknownArgError(ctx, name, i)
+		}
+
+		// If the argument is of bool type, then the next argument
+		// can be a string which can be parsed error free by
+		// strconv.ParseBool, or can be unspecified to mean 'true'.
+		// Counter arguments do not consume the next argument.
+		if isCounterValue(arg.value) {
+			valStr = "true"
+		} else if arg.hasImplicitValue {
+			valStr = arg.implicitValue
+		} else if !isBoolValue(arg.value) {
+			if i + 1 >= len(arguments) {
+				return i, &MissingValueError{ctx.path(), name, argument, i}
+			}
+			i += 1
+			valStr = arguments[i]
+		} else {
+			valStr = "true"
+			if i + 1 < len(arguments) {
+				nextArgStr := arguments[i + 1]
+				_, err := strconv.ParseBool(nextArgStr)
+				if err == nil {
+					i += 1
+					valStr = nextArgStr
 				}
-			default:
-				err := fmt.Errorf("Unexpected argument type while parsing.")
-				return processedCmds, err
 			}
+		}
+	} else if indexOfEqual == 0 {
+		// This is an error
+		return i, &UnknownArgError{ctx.path(), "", argument, i, nil}
+	} else {
+		name := stripped[0:indexOfEqual]
+		valStr = stripped[indexOfEqual + 1:]
+		var exists bool
+		arg, exists = cmd.lookupArg(name)
+		if !exists {
+			return i, cmd.negatedOrUnknownArgError(ctx, name, valStr, i)
+		}
+	}
+
+	return i, cmd.setNamedArg(ctx, arg, valStr, i)
+}
 
-			if !valid {
-				err := fmt.Errorf("Unable to perform type assertion while parsing.")
-				return processedCmds, err
+// isGNUNamedArg returns true if |argument| is a named argument, or a cluster
+// of named arguments, in GNU mode. A lone "-" and negative numbers which do
+// not start with a short name are unnamed arguments.
+func (cmd *Cmd) isGNUNamedArg(argument string) bool {
+	if !strings.HasPrefix(argument, "-") || len(argument) == 1 {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(argument, 64)
+	if err == nil {
+		_, isShort := cmd.lookupShort(argument[1:2])
+		return isShort
+	}
+
+	return true
+}
+
+// parseGNUNamedArg parses the named argument at index |i| of |ctx.arguments|
+// in GNU mode, and returns the index of the last argument consumed. In GNU
+// mode, long names are prefixed with "--" and short names with "-":
+//     --name value
+//     --name=value
+//     -n value
+//     -nvalue
+// Short names should be single characters. Multiple short names can be
+// clustered after a single "-", as in '-xvf file', which is the same as
+// '-x -v -f file'. Bool arguments do not consume the next argument as their
+// value; they are set to 'true' when specified without '='.
+func (cmd *Cmd) parseGNUNamedArg(ctx *parseContext, i int) (int, error) {
+	arguments := ctx.arguments
+	argument := arguments[i]
+	index := i
+	if strings.HasPrefix(argument, "--") {
+		name := argument[2:]
+		var valStr string
+		hasValue := false
+		indexOfEqual := strings.Index(name, "=")
+		if indexOfEqual >= 0 {
+			valStr = name[indexOfEqual + 1:]
+			name = name[:indexOfEqual]
+			hasValue = true
+		}
+
+		arg, exists := cmd.lookupLong(name)
+		if !exists {
+			negated, isNegated := cmd.lookupNegated(name)
+			if isNegated && !hasValue {
+				return i, cmd.setNamedArg(ctx, negated, "false", index)
 			}
+			return i, cmd.negatedOrUnknownArgError(ctx, name, valStr, i)
+		}
+
+		if !hasValue {
+			if arg.hasImplicitValue {
+				valStr = arg.implicitValue
+			} else if isBoolValue(arg.value) {
+				valStr = "true"
+			} else if i + 1 < len(arguments) {
+				i += 1
+				valStr = arguments[i]
+			} else {
+				return i, &MissingValueError{ctx.path(), name, argument, i}
+			}
+		}
+
+		return i, cmd.setNamedArg(ctx, arg, valStr, index)
+	}
+
+	cluster := argument[1:]
+	for j := 0; j < len(cluster); j++ {
+		short := cluster[j:j + 1]
+		arg, exists := cmd.lookupShort(short)
+		if !exists {
+			return i, cmd.unknownArgError(ctx, short, i)
+		}
+
+		if isBoolValue(arg.value) && !arg.hasImplicitValue {
+			err := cmd.setNamedArg(ctx, arg, "true", index)
 			if err != nil {
-				err := fmt.Errorf(
-					"Error parsing value of argument '%s'.\n%s", err.Error())
-				return processedCmds, err
+				return i, err
 			}
+			continue
+		}
 
-			if arg.required {
-				arg.set = true
+		// The rest of the cluster, if any, is the value of a non-bool
+		// argument. Otherwise, the next argument is its value, unless the
+		// argument has an implicit value.
+		valStr := cluster[j + 1:]
+		if len(valStr) == 0 && arg.hasImplicitValue {
+			valStr = arg.implicitValue
+		} else if len(valStr) == 0 {
+			if i + 1 >= len(arguments) {
+				return i, &MissingValueError{ctx.path(), short, argument, i}
 			}
-		} else {
-			// This is not a named argument.
-			cmd.argList = append(cmd.argList, Arg(argument))
+			i += 1
+			valStr = arguments[i]
 		}
+
+		return i, cmd.setNamedArg(ctx, arg, valStr, index)
 	}
 
-	if !cmd.shouldRenderHelp {
-		for _, arg := range cmd.namedArgList {
-			if arg.required && !arg.set {
-				err := fmt.Errorf("Required argument '%s' not specified.", arg.name)
-				return processedCmds, err
+	return i, nil
+}
+
+// SetImplicitValue makes the value of |namedArg| optional on the command line.
+// When the argument is specified without a value, as in '--color' instead
+// of '--color=never', its value is set to |valStr|. Like bool args, such an
+// argument never takes the next argument as its value; it can only be given
+// a value with '=', or in GNU mode, in the same cluster as its short name,
+// as in '-cnever'.
+func (namedArg *NamedArg) SetImplicitValue(valStr string) *NamedArg {
+	namedArg.implicitValue = valStr
+	namedArg.hasImplicitValue = true
+	return namedArg
+}
+
+// needsValue returns true if |namedArg| takes the next argument as its value
+// when it is specified without one.
+func (namedArg *NamedArg) needsValue() bool {
+	return !isBoolValue(namedArg.value) && !namedArg.hasImplicitValue
+}
+
+// Bool args registered with AddBoolArg, except the help arg, can be set to
+// false with '--no-<name>', unless there is a named arg called 'no-<name>'.
+const negationPrefix = "no-"
+
+func (namedArg *NamedArg) isNegatable() bool {
+	_, isBool := namedArg.value.(*boolValue)
+	return isBool && namedArg != namedArg.owner.helpArg
+}
+
+// lookupNegated looks up the bool arg negated by |name|.
+func (cmd *Cmd) lookupNegated(name string) (*NamedArg, bool) {
+	if !strings.HasPrefix(name, negationPrefix) {
+		return nil, false
+	}
+
+	arg, exists := cmd.lookupLong(name[len(negationPrefix):])
+	if !exists || !arg.isNegatable() {
+		return nil, false
+	}
+
+	return arg, true
+}
+
+// negatedOrUnknownArgError returns the error for the unknown argument |name|
+// specified with the value |valStr| by |ctx.arguments[index]|. Negated bool
+// args do not take a value.
+func (cmd *Cmd) negatedOrUnknownArgError(
+	ctx *parseContext, name, valStr string, index int) error {
+	negated, isNegated := cmd.lookupNegated(name)
+	if !isNegated {
+		return cmd.unknownArgError(ctx, name, index)
+	}
+
+	invalidErr := &InvalidValueError{CmdPath: ctx.path(), Name: negated.name}
+	invalidErr.Value = valStr
+	invalidErr.Token = ctx.arguments[index]
+	invalidErr.Index = index
+	invalidErr.Err = fmt.Errorf("Argument '%s' does not take a value.", name)
+	return invalidErr
+}
+
+// inheritedArgs returns the persistent named args of the ancestors of |cmd|,
+// excluding those shadowed by a named arg of |cmd| or of a closer ancestor.
+func (cmd *Cmd) inheritedArgs() []*NamedArg {
+	var args []*NamedArg
+	for ancestor := cmd.parent; ancestor != nil; ancestor = ancestor.parent {
+		for _, arg := range ancestor.namedArgList {
+			if !arg.persistent {
+				continue
+			}
+			found, _ := cmd.lookupLong(arg.name)
+			if found == arg {
+				args = append(args, arg)
 			}
 		}
 	}
 
-	return processedCmds, nil
+	return args
+}
+
+// acceptedArgs returns the named args of |cmd| followed by the named args it
+// inherits from its ancestors.
+func (cmd *Cmd) acceptedArgs() []*NamedArg {
+	args := append([]*NamedArg(nil), cmd.namedArgList...)
+	return append(args, cmd.inheritedArgs()...)
+}
+
+// lookupNamedArg looks up the named arg of |cmd|, or the persistent named arg
+// of its ancestors, whose name or short name, as selected by |isMatch|, is
+// |name|.
+func (cmd *Cmd) lookupNamedArg(
+	name string, isMatch func(arg *NamedArg) bool) (*NamedArg, bool) {
+	for c := cmd; c != nil; c = c.parent {
+		arg, exists := c.namedArgMap[name]
+		if exists && isMatch(arg) && (c == cmd || arg.persistent) {
+			return arg, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupArg looks up the named arg whose name or short name is |name|.
+func (cmd *Cmd) lookupArg(name string) (*NamedArg, bool) {
+	return cmd.lookupNamedArg(name, func(arg *NamedArg) bool {
+		return arg.hasName(name) || arg.short == name
+	})
+}
+
+func (cmd *Cmd) lookupLong(name string) (*NamedArg, bool) {
+	return cmd.lookupNamedArg(name, func(arg *NamedArg) bool {
+		return arg.hasName(name)
+	})
+}
+
+func (cmd *Cmd) lookupShort(short string) (*NamedArg, bool) {
+	return cmd.lookupNamedArg(short, func(arg *NamedArg) bool {
+		return arg.short == short
+	})
+}
+
+// setNamedArg sets the value of |arg| from the command line. |index| is the
+// index of the argument which named |arg|.
+func (cmd *Cmd) setNamedArg(
+	ctx *parseContext, arg *NamedArg, valStr string, index int) error {
+	if arg.deprecated && arg.source != SourceCommandLine {
+		cmd.warnDeprecated("Argument", arg.name, arg.deprecationHint)
+	}
+
+	err := arg.value.Set(valStr)
+	if err != nil {
+		invalidErr := &InvalidValueError{CmdPath: ctx.path(), Name: arg.name, Err: err}
+		invalidErr.Value = valStr
+		invalidErr.Token = ctx.arguments[index]
+		invalidErr.Index = index
+		return invalidErr
+	}
+
+	arg.setSource(SourceCommandLine, "")
+	return nil
 }
 
 func (cmd *Cmd) Args() []Arg {
@@ -430,6 +840,8 @@ func (cmd *Cmd) Args() []Arg {
 
 func (cmd *Cmd) Clear() error {
 	cmd.argList = nil
+	cmd.argIndices = nil
+	cmd.requestedTopic = nil
 
 	for _, namedArg := range cmd.namedArgList {
 		err := namedArg.Reset()
@@ -440,6 +852,15 @@ func (cmd *Cmd) Clear() error {
 		}
 	}
 
+	for _, positional := range cmd.positionals {
+		err := positional.Reset()
+		if err != nil {
+			err = fmt.Errorf(
+				"Unable to clear command '%s'.\n%s'", cmd.name, err.Error())
+			return err
+		}
+	}
+
 	for _, subCmd := range cmd.subCmds {
 		err := subCmd.Clear()
 		if err != nil {
@@ -457,26 +878,7 @@ func (cmd *Cmd) ShouldRenderHelp() bool {
 	return cmd.shouldRenderHelp
 }
 
+// RenderHelp writes the help of |cmd| to standard output. See WriteHelp.
 func (cmd *Cmd) RenderHelp() {
-	fmt.Printf("%s\n\n", cmd.description)
-
-	if len(cmd.subCmds) > 0 {
-		fmt.Printf("Sub-commands:\n")
-		for _, subCmd := range cmd.subCmds {
-			fmt.Printf("     %s\n", subCmd.name)
-		}
-		fmt.Printf("\n")
-	}
-
-	fmt.Printf("Options:\n")
-	for _, arg := range cmd.namedArgList {
-		fmt.Printf("  -%s,  --%s\n", arg.short, arg.name)
-		if arg.required {
-			fmt.Printf("     Required argument.\n")
-		} else {
-			fmt.Printf("     Default value: %s\n", arg.defValStr)
-		}
-		usage := strings.Replace(arg.help, "\n", "\n     ", -1)
-		fmt.Printf("     %s\n", usage)
-	}
+	cmd.WriteHelp(os.Stdout)
 }
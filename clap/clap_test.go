@@ -219,13 +219,17 @@ func TestSubCommand(t *testing.T) {
 	cmd := createTestCmd()
 	err := addSubCmd(cmd)
 	if err != nil {
-		t.Errorf(err.Error());
+		t.Error(err.Error())
 	}
 
-	cmdLine := []string{"subcmd", "-i=10", "-l=20"}
+	// The required arguments of the parent command are required even when a
+	// sub-command is selected.
+	cmdLine := []string{
+		"-i=1", "-l=2", "-u=3", "-x=4", "-b=true", "-f=1.5", "-s=hi",
+		"subcmd", "-i=10", "-l=20"}
 	cmdList, err := cmd.Parse(cmdLine)
 	if err != nil {
-		t.Errorf(err.Error())
+		t.Error(err.Error())
 	}
 
 	if cmdList[1] != "subcmd" || cmdList[0] != "command" {
@@ -0,0 +1,491 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CompletionHint tells the generated shell completion scripts how to complete
+// the value of a named arg.
+type CompletionHint int
+
+const (
+	// No completion for the value.
+	CompleteNone = CompletionHint(0)
+
+	// Complete the value with file names.
+	CompleteFiles = CompletionHint(1)
+
+	// Complete the value with directory names.
+	CompleteDirs = CompletionHint(2)
+)
+
+// CompleteFunc returns the candidate values of a named arg which start with
+// |prefix|. It is called by the binary itself at completion time.
+type CompleteFunc func(prefix string) []string
+
+// The hidden first argument with which completion scripts invoke the binary
+// to compute dynamic completions. It is followed by the words on the command
+// line after the program name, the last of which is the word being
+// completed. The candidates are printed one per line.
+const CompleteCmdName = "__complete"
+
+// SetCompletionHint sets how shell completion scripts complete the value of
+// |namedArg|. Values of enum args are completed with their choices without
+// a hint.
+func (namedArg *NamedArg) SetCompletionHint(hint CompletionHint) *NamedArg {
+	namedArg.completionHint = hint
+	return namedArg
+}
+
+// SetCompleteFunc sets a function which computes the candidate values of
+// |namedArg| at completion time.
+func (namedArg *NamedArg) SetCompleteFunc(fn CompleteFunc) *NamedArg {
+	namedArg.completeFunc = fn
+	return namedArg
+}
+
+// HandleCompletion handles the hidden completion protocol. If |arguments|
+// start with CompleteCmdName, it writes the completion candidates for the
+// rest of |arguments| to |w| and returns true. Otherwise, it returns false
+// and |arguments| should be parsed as usual. It should be called on the root
+// command before calling Parse:
+//
+//     if cmd.HandleCompletion(os.Args[1:], os.Stdout) {
+//         return
+//     }
+func (cmd *Cmd) HandleCompletion(arguments []string, w io.Writer) bool {
+	if len(arguments) == 0 || arguments[0] != CompleteCmdName {
+		return false
+	}
+
+	for _, candidate := range cmd.Complete(arguments[1:]) {
+		fmt.Fprintln(w, candidate)
+	}
+
+	return true
+}
+
+// Complete returns the completion candidates for the last word in |words|,
+// which are the words on the command line after the program name.
+func (cmd *Cmd) Complete(words []string) []string {
+	current := ""
+	if len(words) > 0 {
+		current = words[len(words) - 1]
+		words = words[:len(words) - 1]
+	}
+
+	completingCmd := cmd
+	var valueArg *NamedArg
+	positionalSeen := false
+	helpSeen := false
+	for _, word := range words {
+		if valueArg != nil {
+			valueArg = nil
+			continue
+		}
+
+		if helpSeen {
+			// The arguments of the built-in help sub-command are names of
+			// sub-commands and help topics.
+			subCmd, exists := completingCmd.subCmds[word]
+			if exists {
+				completingCmd = subCmd
+			} else {
+				positionalSeen = true
+			}
+			continue
+		}
+
+		if strings.HasPrefix(word, "-") {
+			name := strings.TrimLeft(word, "-")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			arg, exists := completingCmd.lookupArg(name)
+			if exists && arg.needsValue() {
+				valueArg = arg
+			}
+			continue
+		}
+
+		subCmd, exists := completingCmd.subCmds[word]
+		if exists && !positionalSeen {
+			completingCmd = subCmd
+		} else if word == helpCmdName && !positionalSeen && completingCmd.hasHelpCmd() {
+			helpSeen = true
+		} else {
+			positionalSeen = true
+		}
+	}
+
+	if valueArg != nil {
+		return valueArg.completeValue(current)
+	}
+
+	var candidates []string
+	if strings.HasPrefix(current, "-") {
+		indexOfEqual := strings.Index(current, "=")
+		if indexOfEqual >= 0 {
+			arg, exists := completingCmd.lookupArg(strings.TrimLeft(current[:indexOfEqual], "-"))
+			if !exists {
+				return nil
+			}
+			for _, value := range arg.completeValue(current[indexOfEqual + 1:]) {
+				candidates = append(candidates, current[:indexOfEqual + 1] + value)
+			}
+			return candidates
+		}
+
+		for _, flag := range completingCmd.flagNames() {
+			if strings.HasPrefix(flag, current) {
+				candidates = append(candidates, flag)
+			}
+		}
+		return candidates
+	}
+
+	if positionalSeen {
+		return nil
+	}
+	names := completingCmd.sortedSubCmdNames()
+	if helpSeen {
+		names = completingCmd.helpTargetNames()
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, current) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	return candidates
+}
+
+func (namedArg *NamedArg) completeValue(prefix string) []string {
+	var values []string
+	if namedArg.completeFunc != nil {
+		values = namedArg.completeFunc(prefix)
+	} else {
+		choices, hasChoices := namedArg.value.(choicesValue)
+		if hasChoices {
+			values = choices.choices()
+		}
+	}
+
+	var candidates []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			candidates = append(candidates, value)
+		}
+	}
+
+	return candidates
+}
+
+// flagNames returns the long and short forms of all named args of |cmd|.
+func (cmd *Cmd) flagNames() []string {
+	var flags []string
+	for _, arg := range visibleArgs(cmd.acceptedArgs()) {
+		flags = append(flags, "--" + arg.name)
+		if arg.isNegatable() {
+			flags = append(flags, "--" + negationPrefix + arg.name)
+		}
+		if len(arg.short) > 0 {
+			flags = append(flags, "-" + arg.short)
+		}
+	}
+
+	return flags
+}
+
+// sortedSubCmdNames returns the names of the sub-commands of |cmd| which are
+// not hidden, excluding aliases, in sorted order.
+func (cmd *Cmd) sortedSubCmdNames() []string {
+	var names []string
+	for name, subCmd := range cmd.subCmds {
+		if name == subCmd.name && !subCmd.hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// A command in a command tree along with the path of command names leading
+// to it from the root.
+type cmdPathEntry struct {
+	path string
+	cmd *Cmd
+}
+
+// cmdPaths lists |cmd| and all its descendant commands in depth first order.
+func (cmd *Cmd) cmdPaths() []cmdPathEntry {
+	var entries []cmdPathEntry
+	var walk func(path string, c *Cmd)
+	walk = func(path string, c *Cmd) {
+		entries = append(entries, cmdPathEntry{path, c})
+		for _, name := range c.sortedSubCmdNames() {
+			walk(path + " " + name, c.subCmds[name])
+		}
+	}
+	walk(cmd.name, cmd)
+
+	return entries
+}
+
+// argPatterns returns the shell case pattern matching the flag forms of |arg|.
+func argPatterns(arg *NamedArg) string {
+	pattern := "--" + arg.name
+	if len(arg.short) > 0 {
+		pattern = "-" + arg.short + "|" + pattern
+	}
+
+	return pattern
+}
+
+// shellQuote quotes |s| for use as a single word in a POSIX shell or fish.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "'\\''", -1) + "'"
+}
+
+func completionFuncName(name string) string {
+	mapper := func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}
+
+	return "_" + strings.Map(mapper, name)
+}
+
+// GenBashCompletion writes a bash completion script for |cmd| to |w|.
+func (cmd *Cmd) GenBashCompletion(w io.Writer) error {
+	funcName := completionFuncName(cmd.name) + "_complete"
+	entries := cmd.cmdPaths()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# bash completion for %s\n\n", cmd.name)
+	fmt.Fprintf(&buf, "%s() {\n", funcName)
+	fmt.Fprintf(&buf, "    local cur prev cmdpath i\n")
+	fmt.Fprintf(&buf, "    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(&buf, "    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(&buf, "    cmdpath=%s\n", shellQuote(cmd.name))
+	if len(entries) > 1 {
+		var subPaths []string
+		for _, entry := range entries[1:] {
+			subPaths = append(subPaths, shellQuote(entry.path))
+		}
+		fmt.Fprintf(&buf, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+		fmt.Fprintf(&buf, "        case \"${cmdpath} ${COMP_WORDS[i]}\" in\n")
+		fmt.Fprintf(&buf, "            %s)\n", strings.Join(subPaths, "|"))
+		fmt.Fprintf(&buf, "                cmdpath=\"${cmdpath} ${COMP_WORDS[i]}\";;\n")
+		fmt.Fprintf(&buf, "        esac\n")
+		fmt.Fprintf(&buf, "    done\n")
+	}
+	fmt.Fprintf(&buf, "\n    case \"${cmdpath}\" in\n")
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "        %s)\n", shellQuote(entry.path))
+		fmt.Fprintf(&buf, "            case \"${prev}\" in\n")
+		for _, arg := range visibleArgs(entry.cmd.acceptedArgs()) {
+			if !arg.needsValue() {
+				continue
+			}
+			fmt.Fprintf(&buf, "                %s)\n", argPatterns(arg))
+			_, hasChoices := arg.value.(choicesValue)
+			switch {
+			case arg.completeFunc != nil:
+				fmt.Fprintf(
+					&buf, "                    COMPREPLY=($(\"${COMP_WORDS[0]}\" %s " +
+					"\"${COMP_WORDS[@]:1:COMP_CWORD}\"))\n", CompleteCmdName)
+			case arg.completionHint == CompleteFiles:
+				fmt.Fprintf(&buf, "                    COMPREPLY=($(compgen -f -- \"${cur}\"))\n")
+			case arg.completionHint == CompleteDirs:
+				fmt.Fprintf(&buf, "                    COMPREPLY=($(compgen -d -- \"${cur}\"))\n")
+			case hasChoices:
+				fmt.Fprintf(
+					&buf, "                    COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n",
+					shellQuote(strings.Join(arg.completeValue(""), " ")))
+			default:
+				fmt.Fprintf(&buf, "                    COMPREPLY=()\n")
+			}
+			fmt.Fprintf(&buf, "                    return;;\n")
+		}
+		fmt.Fprintf(&buf, "            esac\n")
+		words := append(entry.cmd.sortedSubCmdNames(), entry.cmd.flagNames()...)
+		fmt.Fprintf(
+			&buf, "            COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n",
+			shellQuote(strings.Join(words, " ")))
+		fmt.Fprintf(&buf, "            ;;\n")
+	}
+	fmt.Fprintf(&buf, "    esac\n")
+	fmt.Fprintf(&buf, "}\n\n")
+	fmt.Fprintf(&buf, "complete -F %s %s\n", funcName, cmd.name)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// GenZshCompletion writes a zsh completion script for |cmd| to |w|.
+func (cmd *Cmd) GenZshCompletion(w io.Writer) error {
+	funcName := completionFuncName(cmd.name)
+	entries := cmd.cmdPaths()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "#compdef %s\n\n", cmd.name)
+	fmt.Fprintf(&buf, "%s() {\n", funcName)
+	fmt.Fprintf(&buf, "    local cmdpath i\n")
+	fmt.Fprintf(&buf, "    local prev=\"${words[CURRENT-1]}\"\n")
+	fmt.Fprintf(&buf, "    cmdpath=%s\n", shellQuote(cmd.name))
+	if len(entries) > 1 {
+		var subPaths []string
+		for _, entry := range entries[1:] {
+			subPaths = append(subPaths, shellQuote(entry.path))
+		}
+		fmt.Fprintf(&buf, "    for ((i = 2; i < CURRENT; i++)); do\n")
+		fmt.Fprintf(&buf, "        case \"${cmdpath} ${words[i]}\" in\n")
+		fmt.Fprintf(&buf, "            %s)\n", strings.Join(subPaths, "|"))
+		fmt.Fprintf(&buf, "                cmdpath=\"${cmdpath} ${words[i]}\";;\n")
+		fmt.Fprintf(&buf, "        esac\n")
+		fmt.Fprintf(&buf, "    done\n")
+	}
+	fmt.Fprintf(&buf, "\n    case \"${cmdpath}\" in\n")
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "        %s)\n", shellQuote(entry.path))
+		fmt.Fprintf(&buf, "            case \"${prev}\" in\n")
+		for _, arg := range visibleArgs(entry.cmd.acceptedArgs()) {
+			if !arg.needsValue() {
+				continue
+			}
+			fmt.Fprintf(&buf, "                %s)\n", argPatterns(arg))
+			_, hasChoices := arg.value.(choicesValue)
+			switch {
+			case arg.completeFunc != nil:
+				fmt.Fprintf(
+					&buf, "                    compadd -- ${(f)\"$(${words[1]} %s " +
+					"${words[2,CURRENT]})\"}\n", CompleteCmdName)
+			case arg.completionHint == CompleteFiles:
+				fmt.Fprintf(&buf, "                    _files\n")
+			case arg.completionHint == CompleteDirs:
+				fmt.Fprintf(&buf, "                    _files -/\n")
+			case hasChoices:
+				fmt.Fprintf(
+					&buf, "                    compadd -- %s\n",
+					strings.Join(quoteAll(arg.completeValue("")), " "))
+			}
+			fmt.Fprintf(&buf, "                    return;;\n")
+		}
+		fmt.Fprintf(&buf, "            esac\n")
+		words := append(entry.cmd.sortedSubCmdNames(), entry.cmd.flagNames()...)
+		fmt.Fprintf(&buf, "            compadd -- %s\n", strings.Join(quoteAll(words), " "))
+		fmt.Fprintf(&buf, "            ;;\n")
+	}
+	fmt.Fprintf(&buf, "    esac\n")
+	fmt.Fprintf(&buf, "}\n\n")
+	fmt.Fprintf(&buf, "compdef %s %s\n", funcName, cmd.name)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// GenFishCompletion writes a fish completion script for |cmd| to |w|.
+func (cmd *Cmd) GenFishCompletion(w io.Writer) error {
+	pathFuncName := "_" + completionFuncName(cmd.name) + "_cmdpath"
+	entries := cmd.cmdPaths()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# fish completion for %s\n\n", cmd.name)
+	fmt.Fprintf(&buf, "function %s\n", pathFuncName)
+	fmt.Fprintf(&buf, "    set -l cmdpath %s\n", shellQuote(cmd.name))
+	if len(entries) > 1 {
+		var subPaths []string
+		for _, entry := range entries[1:] {
+			subPaths = append(subPaths, shellQuote(entry.path))
+		}
+		fmt.Fprintf(&buf, "    for word in (commandline -opc)[2..-1]\n")
+		fmt.Fprintf(&buf, "        switch \"$cmdpath $word\"\n")
+		fmt.Fprintf(&buf, "            case %s\n", strings.Join(subPaths, " "))
+		fmt.Fprintf(&buf, "                set cmdpath \"$cmdpath $word\"\n")
+		fmt.Fprintf(&buf, "        end\n")
+		fmt.Fprintf(&buf, "    end\n")
+	}
+	fmt.Fprintf(&buf, "    echo $cmdpath\n")
+	fmt.Fprintf(&buf, "end\n\n")
+	fmt.Fprintf(&buf, "complete -c %s -f\n", cmd.name)
+	for _, entry := range entries {
+		condition := shellQuote(fmt.Sprintf("test (%s) = \"%s\"", pathFuncName, entry.path))
+		prefix := fmt.Sprintf("complete -c %s -n %s", cmd.name, condition)
+		for _, name := range entry.cmd.sortedSubCmdNames() {
+			fmt.Fprintf(
+				&buf, "%s -a %s -d %s\n", prefix, shellQuote(name),
+				shellQuote(firstLine(entry.cmd.subCmds[name].description)))
+		}
+		for _, arg := range visibleArgs(entry.cmd.acceptedArgs()) {
+			line := prefix
+			if len(arg.short) > 0 {
+				line += " -s " + shellQuote(arg.short)
+			}
+			line += " -l " + shellQuote(arg.name)
+			if arg.needsValue() {
+				_, hasChoices := arg.value.(choicesValue)
+				switch {
+				case arg.completeFunc != nil:
+					line += fmt.Sprintf(
+						" -r -a '(%s %s (commandline -opc)[2..-1] (commandline -ct))'",
+						cmd.name, CompleteCmdName)
+				case arg.completionHint == CompleteFiles:
+					line += " -r -F"
+				case arg.completionHint == CompleteDirs:
+					line += " -r -a '(__fish_complete_directories)'"
+				case hasChoices:
+					line += " -r -a " + shellQuote(strings.Join(arg.completeValue(""), " "))
+				default:
+					line += " -r"
+				}
+			}
+			line += " -d " + shellQuote(firstLine(arg.help))
+			fmt.Fprintf(&buf, "%s\n", line)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func quoteAll(words []string) []string {
+	var quoted []string
+	for _, word := range words {
+		quoted = append(quoted, shellQuote(word))
+	}
+
+	return quoted
+}
+
+func firstLine(s string) string {
+	indexOfNewline := strings.Index(s, "\n")
+	if indexOfNewline >= 0 {
+		return s[:indexOfNewline]
+	}
+
+	return s
+}
@@ -0,0 +1,113 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var complVerbose bool
+var complFile string
+var complMode string
+var complHost string
+var complName string
+
+func createCompletionTestCmd() *Cmd {
+	cmd := NewCmd("tool", "A test tool.")
+	cmd.AddBoolArg("verbose", "v", &complVerbose, false, false, "Verbose output.")
+	cmd.AddStringArg("file", "f", &complFile, "", false, "Input file.").SetCompletionHint(CompleteFiles)
+	cmd.AddEnumArg("mode", "m", &complMode, "fast", []string{"fast", "slow"}, false, "Mode.")
+	cmd.AddStringArg("host", "", &complHost, "", false, "Host.").SetCompleteFunc(
+		func(prefix string) []string {
+			return []string{"alpha", "beta", "gamma"}
+		})
+
+	subCmd := NewCmd("serve", "Serve files.")
+	subCmd.AddStringArg("name", "n", &complName, "", false, "Name.")
+	cmd.AddSubCmd(subCmd)
+	cmd.AddSubCmd(NewCmd("status", "Show status."))
+
+	return cmd
+}
+
+func TestComplete(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	completions := map[string][]string{
+		"": []string{"serve", "status"},
+		"s": []string{"serve", "status"},
+		"se": []string{"serve"},
+		"--m": []string{"--mode"},
+		"--mode ": []string{"fast", "slow"},
+		"-m s": []string{"slow"},
+		"--mode=f": []string{"--mode=fast"},
+		"--host b": []string{"beta"},
+		"-v st": []string{"status"},
+		"serve --n": []string{"--name"},
+		"serve -": []string{"--help", "-h", "--name", "-n"},
+		"serve x ": nil,
+	}
+	for line, expected := range completions {
+		words := strings.Split(line, " ")
+		candidates := cmd.Complete(words)
+		if !reflect.DeepEqual(candidates, expected) {
+			t.Errorf("Completions for '%s' are '%v'; expecting '%v'.", line, candidates, expected)
+		}
+	}
+}
+
+func TestHandleCompletion(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	var out bytes.Buffer
+	if cmd.HandleCompletion([]string{"--mode", "fast"}, &out) {
+		t.Errorf("Regular arguments handled as a completion request.")
+	}
+
+	handled := cmd.HandleCompletion([]string{CompleteCmdName, "--host", ""}, &out)
+	if !handled {
+		t.Errorf("Completion request not handled.")
+	}
+	if out.String() != "alpha\nbeta\ngamma\n" {
+		t.Errorf("Unexpected completion output '%s'.", out.String())
+	}
+}
+
+func TestGenCompletion(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	generators := map[string]func(*Cmd, *bytes.Buffer) error{
+		"bash": func(c *Cmd, b *bytes.Buffer) error { return c.GenBashCompletion(b) },
+		"zsh": func(c *Cmd, b *bytes.Buffer) error { return c.GenZshCompletion(b) },
+		"fish": func(c *Cmd, b *bytes.Buffer) error { return c.GenFishCompletion(b) },
+	}
+	for shell, gen := range generators {
+		var buf bytes.Buffer
+		err := gen(cmd, &buf)
+		if err != nil {
+			t.Errorf("Error generating %s completion:\n%s", shell, err.Error())
+			continue
+		}
+
+		script := buf.String()
+		for _, word := range []string{"serve", "status", "name", "fast", CompleteCmdName} {
+			if !strings.Contains(script, word) {
+				t.Errorf("The %s completion script does not contain '%s'.", shell, word)
+			}
+		}
+	}
+}
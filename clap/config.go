@@ -0,0 +1,342 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Argument values of a command and its sub-commands can be loaded from
+// config files. Keys in a config file are the long names of named args.
+// Values for the args of a sub-command are listed in a section named after
+// the sub-command. In INI files, sections of nested sub-commands are named
+// by joining the sub-command names with '.':
+//
+//     port = 8080
+//     include = /usr/include
+//     include = /usr/local/include
+//
+//     [sub]
+//     retries = 3
+//
+//     [sub.subsub]
+//     verbose = true
+//
+// In JSON files, sections are nested objects and the values of slice
+// arguments can be listed in arrays:
+//
+//     {
+//       "port": 8080,
+//       "include": ["/usr/include", "/usr/local/include"],
+//       "sub": {
+//         "retries": 3,
+//         "subsub": {"verbose": true}
+//       }
+//     }
+//
+// Values from config files take precedence over default values, but not over
+// values specified on the command line or in environment variables. If
+// multiple config files are loaded, the values from a later file replace
+// those from earlier files.
+
+// A value of a named arg read from a config file.
+type configValue struct {
+	valStr string
+	fileName string
+	line int
+}
+
+// LoadConfigFile loads argument values for |cmd| and its sub-commands from
+// the file at |path|. Files with a '.json' extension are parsed as JSON, and
+// all other files are parsed as INI files.
+func (cmd *Cmd) LoadConfigFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Unable to open config file '%s'.\n%s", path, err.Error())
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return cmd.LoadJSONConfig(file, path)
+	}
+
+	return cmd.LoadINIConfig(file, path)
+}
+
+// LoadINIConfig loads argument values for |cmd| and its sub-commands from
+// INI formatted data read from |r|. |fileName| is used in error messages.
+func (cmd *Cmd) LoadINIConfig(r io.Reader, fileName string) error {
+	values := make(map[*NamedArg][]configValue)
+	sectionCmd := cmd
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line += 1
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return fmt.Errorf(
+					"%s:%d: Missing ']' in section header.", fileName, line)
+			}
+
+			sectionCmd = cmd
+			section := strings.TrimSpace(text[1:len(text) - 1])
+			for _, subCmdName := range strings.Split(section, ".") {
+				subCmd, exists := sectionCmd.subCmds[strings.TrimSpace(subCmdName)]
+				if !exists {
+					return fmt.Errorf(
+						"%s:%d: Unknown sub-command '%s' in section '%s'.",
+						fileName, line, subCmdName, section)
+				}
+				sectionCmd = subCmd
+			}
+			continue
+		}
+
+		indexOfEqual := strings.Index(text, "=")
+		if indexOfEqual <= 0 {
+			return fmt.Errorf(
+				"%s:%d: Expecting 'name = value'; found '%s'.", fileName, line, text)
+		}
+
+		key := strings.TrimSpace(text[:indexOfEqual])
+		arg, err := sectionCmd.configArg(key, fileName, line)
+		if err != nil {
+			return err
+		}
+
+		valStr := strings.TrimSpace(text[indexOfEqual + 1:])
+		if len(valStr) >= 2 && strings.HasPrefix(valStr, "\"") && strings.HasSuffix(valStr, "\"") {
+			valStr, err = strconv.Unquote(valStr)
+			if err != nil {
+				return fmt.Errorf(
+					"%s:%d: Invalid quoted value for '%s'.", fileName, line, key)
+			}
+		}
+
+		values[arg] = append(values[arg], configValue{valStr, fileName, line})
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return fmt.Errorf("Error reading config file '%s'.\n%s", fileName, err.Error())
+	}
+
+	commitConfigValues(values)
+	return nil
+}
+
+// LoadJSONConfig loads argument values for |cmd| and its sub-commands from
+// JSON data read from |r|. |fileName| is used in error messages.
+func (cmd *Cmd) LoadJSONConfig(r io.Reader, fileName string) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("Error reading config file '%s'.\n%s", fileName, err.Error())
+	}
+
+	parser := new(jsonConfigParser)
+	parser.data = data
+	parser.fileName = fileName
+	parser.decoder = json.NewDecoder(bytes.NewReader(data))
+	parser.decoder.UseNumber()
+
+	values := make(map[*NamedArg][]configValue)
+	tok, err := parser.token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf(
+			"%s:%d: Expecting a JSON object at the top level.",
+			fileName, parser.line())
+	}
+
+	err = parser.parseObject(cmd, values)
+	if err != nil {
+		return err
+	}
+
+	commitConfigValues(values)
+	return nil
+}
+
+type jsonConfigParser struct {
+	data []byte
+	fileName string
+	decoder *json.Decoder
+}
+
+// line returns the line number of the last token read.
+func (parser *jsonConfigParser) line() int {
+	offset := parser.decoder.InputOffset()
+	return 1 + bytes.Count(parser.data[:offset], []byte("\n"))
+}
+
+func (parser *jsonConfigParser) token() (json.Token, error) {
+	tok, err := parser.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%s:%d: Invalid JSON.\n%s", parser.fileName, parser.line(), err.Error())
+	}
+
+	return tok, nil
+}
+
+// parseObject parses the members of an object, whose opening '{' has already
+// been read, as argument values of |cmd|.
+func (parser *jsonConfigParser) parseObject(
+	cmd *Cmd, values map[*NamedArg][]configValue) error {
+	for parser.decoder.More() {
+		tok, err := parser.token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		line := parser.line()
+
+		tok, err = parser.token()
+		if err != nil {
+			return err
+		}
+
+		if tok == json.Delim('{') {
+			subCmd, exists := cmd.subCmds[key]
+			if !exists {
+				return fmt.Errorf(
+					"%s:%d: Unknown sub-command '%s' of command '%s'.",
+					parser.fileName, line, key, cmd.name)
+			}
+			err = parser.parseObject(subCmd, values)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		arg, err := cmd.configArg(key, parser.fileName, line)
+		if err != nil {
+			return err
+		}
+
+		if tok == json.Delim('[') {
+			for parser.decoder.More() {
+				tok, err = parser.token()
+				if err != nil {
+					return err
+				}
+				valStr, err := parser.scalar(key, tok)
+				if err != nil {
+					return err
+				}
+				values[arg] = append(
+					values[arg], configValue{valStr, parser.fileName, parser.line()})
+			}
+			// Read the closing ']'.
+			_, err = parser.token()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		valStr, err := parser.scalar(key, tok)
+		if err != nil {
+			return err
+		}
+		values[arg] = append(values[arg], configValue{valStr, parser.fileName, line})
+	}
+
+	// Read the closing '}'.
+	_, err := parser.token()
+	return err
+}
+
+func (parser *jsonConfigParser) scalar(key string, tok json.Token) (string, error) {
+	switch val := tok.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	}
+
+	return "", fmt.Errorf(
+		"%s:%d: Invalid value for '%s'; expecting a string, number or bool.",
+		parser.fileName, parser.line(), key)
+}
+
+// configArg returns the named arg of |cmd| with long name |key|.
+func (cmd *Cmd) configArg(key, fileName string, line int) (*NamedArg, error) {
+	arg, exists := cmd.namedArgMap[key]
+	if !exists || arg.name != key || arg == cmd.helpArg {
+		return nil, fmt.Errorf(
+			"%s:%d: Unknown argument '%s' for command '%s'.",
+			fileName, line, key, cmd.name)
+	}
+
+	return arg, nil
+}
+
+func commitConfigValues(values map[*NamedArg][]configValue) {
+	for arg, argValues := range values {
+		arg.config = argValues
+	}
+}
+
+// applyConfig sets the values of the named args which were not specified on
+// the command line or in the environment from the loaded config files.
+func (cmd *Cmd) applyConfig(ctx *parseContext) error {
+	for _, arg := range cmd.namedArgList {
+		if arg.isSet() || len(arg.config) == 0 {
+			continue
+		}
+
+		repeated, isRepeated := arg.value.(repeatedValue)
+		if isRepeated {
+			repeated.reset()
+		}
+		for _, value := range arg.config {
+			err := arg.value.Set(value.valStr)
+			if err != nil {
+				invalidErr := &InvalidValueError{CmdPath: ctx.path(), Name: arg.name, Err: err}
+				invalidErr.Value = value.valStr
+				invalidErr.Index = -1
+				invalidErr.File = value.fileName
+				invalidErr.Line = value.line
+				return invalidErr
+			}
+		}
+		last := arg.config[len(arg.config) - 1]
+		arg.setSource(SourceConfig, fmt.Sprintf("%s:%d", last.fileName, last.line))
+	}
+
+	return nil
+}
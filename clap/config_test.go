@@ -0,0 +1,183 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type configTestArgs struct {
+	port int
+	host string
+	includes []string
+	name string
+}
+
+func createConfigTestCmd(args *configTestArgs) *Cmd {
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddIntArg("port", "p", &args.port, 80, false, "Port.")
+	cmd.AddStringArg("host", "", &args.host, "localhost", false, "Host.").SetEnv("TEST_CLAP_CONFIG_HOST")
+	cmd.AddStringSliceArg("include", "I", &args.includes, nil, false, "Include paths.")
+
+	subCmd := NewCmd("sub", "A test sub-command.")
+	subCmd.AddStringArg("name", "n", &args.name, "", true, "Name.")
+	cmd.AddSubCmd(subCmd)
+
+	return cmd
+}
+
+func TestINIConfig(t *testing.T) {
+	args := new(configTestArgs)
+	cmd := createConfigTestCmd(args)
+	err := cmd.LoadConfigFile("test_data/config.ini")
+	if err != nil {
+		t.Errorf("Error loading config file:\n%s", err.Error())
+		return
+	}
+
+	_, err = cmd.Parse([]string{"-I", "/tmp"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.port != 8080 {
+		t.Errorf("Argument 'port' has value '%d'; expecting '%d'.", args.port, 8080)
+	}
+	if !reflect.DeepEqual(args.includes, []string{"/tmp"}) {
+		t.Errorf("Argument 'include' has value '%v'; expecting '[/tmp]'.", args.includes)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse(nil)
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	expected := []string{"/usr/include", "/usr/local/include"}
+	if !reflect.DeepEqual(args.includes, expected) {
+		t.Errorf("Argument 'include' has value '%v'; expecting '%v'.", args.includes, expected)
+	}
+
+	// The required argument of the sub-command is satisfied by the config.
+	_, err = cmd.Parse([]string{"sub"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.name != "from config" {
+		t.Errorf("Argument 'name' has value '%s'; expecting '%s'.", args.name, "from config")
+	}
+}
+
+func TestJSONConfig(t *testing.T) {
+	args := new(configTestArgs)
+	cmd := createConfigTestCmd(args)
+	err := cmd.LoadConfigFile("test_data/config.json")
+	if err != nil {
+		t.Errorf("Error loading config file:\n%s", err.Error())
+		return
+	}
+
+	_, err = cmd.Parse([]string{"sub", "-n", "from command line"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.name != "from command line" {
+		t.Errorf(
+			"Argument 'name' has value '%s'; expecting '%s'.", args.name, "from command line")
+	}
+
+	_, err = cmd.Parse(nil)
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.port != 9090 {
+		t.Errorf("Argument 'port' has value '%d'; expecting '%d'.", args.port, 9090)
+	}
+	if !reflect.DeepEqual(args.includes, []string{"/opt/include"}) {
+		t.Errorf("Argument 'include' has value '%v'; expecting '[/opt/include]'.", args.includes)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	args := new(configTestArgs)
+	cmd := createConfigTestCmd(args)
+	err := cmd.LoadINIConfig(strings.NewReader("host = config\n"), "test.ini")
+	if err != nil {
+		t.Errorf("Error loading config:\n%s", err.Error())
+		return
+	}
+
+	os.Setenv("TEST_CLAP_CONFIG_HOST", "env")
+	defer os.Unsetenv("TEST_CLAP_CONFIG_HOST")
+
+	_, err = cmd.Parse(nil)
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.host != "env" {
+		t.Errorf("Argument 'host' has value '%s'; expecting '%s'.", args.host, "env")
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	configs := map[string]string{
+		"test.ini:3": "port = 1\n\nhots = example.com\n",
+		"test.ini:2": "# Comment\n[sbu]\n",
+		"test.json:3": "{\n  \"port\": 1,\n  \"prot\": 2\n}",
+		"test.json:2": "{\n  \"sbu\": {}\n}",
+	}
+	for location, config := range configs {
+		args := new(configTestArgs)
+		cmd := createConfigTestCmd(args)
+
+		var err error
+		if strings.HasPrefix(location, "test.ini") {
+			err = cmd.LoadINIConfig(strings.NewReader(config), "test.ini")
+		} else {
+			err = cmd.LoadJSONConfig(strings.NewReader(config), "test.json")
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), location + ":") {
+			t.Errorf("Expecting an error at '%s'. Got '%v'.", location, err)
+		}
+	}
+
+	args := new(configTestArgs)
+	cmd := createConfigTestCmd(args)
+	err := cmd.LoadINIConfig(strings.NewReader("port = eighty\n"), "test.ini")
+	if err != nil {
+		t.Errorf("Error loading config:\n%s", err.Error())
+		return
+	}
+
+	_, err = cmd.Parse(nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "test.ini:1:") {
+		t.Errorf("Expecting an error at 'test.ini:1'. Got '%v'.", err)
+	}
+}
@@ -0,0 +1,106 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCounterArg(t *testing.T) {
+	var verbosity int
+	var files []string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddCounterArg("verbose", "v", &verbosity, 1, false, "Verbosity.")
+	cmd.AddStringSlicePositional("files", &files, "Files.").SetOptional()
+
+	cases := []struct {
+		gnu bool
+		arguments []string
+		expected int
+	}{
+		{false, []string{"a"}, 1},
+		{false, []string{"-v", "-v", "--verbose", "a"}, 3},
+		{false, []string{"-v", "1"}, 1},
+		{false, []string{"--verbose=5", "-v"}, 6},
+		{true, []string{"-vvv", "a"}, 3},
+		{true, []string{"-v", "--verbose", "a"}, 2},
+	}
+
+	for _, c := range cases {
+		cmd.Clear()
+		cmd.gnuMode = c.gnu
+		_, err := cmd.Parse(c.arguments)
+		if err != nil {
+			t.Errorf("Error parsing '%s'.\n%s", c.arguments, err.Error())
+			continue
+		}
+		if verbosity != c.expected {
+			t.Errorf(
+				"Parsing '%s' counted %d; expecting %d.", c.arguments, verbosity, c.expected)
+		}
+	}
+
+	result, err := cmd.ParseArgs([]string{"-vv"})
+	if err != nil || result.Int("verbose") != 2 {
+		t.Errorf("Counter argument not counted by ParseArgs.")
+	}
+}
+
+func TestNegatedBoolArg(t *testing.T) {
+	var color, cache bool
+	var noCache string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("color", "c", &color, true, false, "Color output.")
+	cmd.AddBoolArg("cache", "", &cache, true, false, "Use the cache.")
+	cmd.AddStringArg("no-cache", "", &noCache, "", false, "An explicit negative name.")
+
+	for _, gnu := range []bool{false, true} {
+		cmd.Clear()
+		cmd.gnuMode = gnu
+		_, err := cmd.Parse([]string{"--no-color"})
+		if err != nil {
+			t.Errorf("Error parsing '--no-color'.\n%s", err.Error())
+			continue
+		}
+		if color {
+			t.Errorf("Argument 'color' not negated by '--no-color'.")
+		}
+		if cmd.Source("color") != SourceCommandLine {
+			t.Errorf("Negated argument 'color' not reported as set on the command line.")
+		}
+	}
+
+	cmd.Clear()
+	_, err := cmd.Parse([]string{"--no-cache", "x"})
+	if err != nil || noCache != "x" || !cache {
+		t.Errorf("Explicit argument 'no-cache' did not take precedence over negation.")
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--no-color=true"})
+	var invalidErr *InvalidValueError
+	if !errors.As(err, &invalidErr) || invalidErr.Name != "color" {
+		t.Errorf("Expecting an invalid value error for '--no-color=true'; got '%v'.", err)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--no-help"})
+	if err == nil {
+		t.Errorf("Expecting an error for '--no-help'.")
+	}
+}
@@ -0,0 +1,276 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// This file implements generation of reference documentation for a command
+// tree: roff man pages and a Markdown document.
+
+// argDocNotes returns notes about |arg| which are listed after its help text
+// in generated documentation.
+func (cmd *Cmd) argDocNotes(arg *NamedArg) []string {
+	var notes []string
+	if arg.required {
+		notes = append(notes, "Required argument.")
+	} else if len(arg.defValStr) > 0 {
+		notes = append(notes, fmt.Sprintf("Default value: %s", arg.defValStr))
+	}
+	if isRepeatedValue(arg.value) {
+		notes = append(notes, "Can be specified multiple times.")
+	}
+	choices, hasChoices := arg.value.(choicesValue)
+	if hasChoices {
+		notes = append(
+			notes, fmt.Sprintf("Allowed values: %s", strings.Join(choices.choices(), ", ")))
+	}
+	envVar := cmd.envVarOf(arg)
+	if len(envVar) > 0 {
+		notes = append(notes, fmt.Sprintf("Environment variable: %s", envVar))
+	}
+	if arg.hasImplicitValue {
+		notes = append(notes, fmt.Sprintf("Value if specified without one: %s", arg.implicitValue))
+	}
+	if isMapValue(arg.value) {
+		notes = append(notes, fmt.Sprintf("Value format: %s", mapValueFormat))
+	}
+	if len(arg.aliases) > 0 {
+		notes = append(notes, fmt.Sprintf("Aliases: %s", strings.Join(arg.aliases, ", ")))
+	}
+	if arg.deprecated {
+		notes = append(notes, strings.TrimSpace("Deprecated. " + arg.deprecationHint))
+	}
+
+	return notes
+}
+
+// roffEscape escapes |s| for use as text in a roff document.
+func roffEscape(s string) string {
+	s = strings.Replace(s, "\\", "\\e", -1)
+	s = strings.Replace(s, "-", "\\-", -1)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// manPageName returns the name of the man page of the command at |path|.
+func manPageName(path string) string {
+	return strings.Replace(path, " ", "-", -1)
+}
+
+// GenManPages writes a man page in section |section| for |cmd| and each of
+// its descendant commands to the directory |dir|. The man page of a
+// sub-command is named after its command path, as in 'tool-sub.1'.
+func (cmd *Cmd) GenManPages(dir string, section int) error {
+	for _, entry := range cmd.cmdPaths() {
+		fileName := filepath.Join(dir, fmt.Sprintf("%s.%d", manPageName(entry.path), section))
+		file, err := os.Create(fileName)
+		if err != nil {
+			return fmt.Errorf("Unable to create man page '%s'.\n%s", fileName, err.Error())
+		}
+
+		err = entry.cmd.writeManPage(file, entry.path, section)
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("Unable to write man page '%s'.\n%s", fileName, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// WriteManPage writes the man page of |cmd| in section |section| to |w|.
+func (cmd *Cmd) WriteManPage(w io.Writer, section int) error {
+	return cmd.writeManPage(w, cmd.commandPath(), section)
+}
+
+// commandPath returns the names of the commands from the root command to
+// |cmd| separated by spaces.
+func (cmd *Cmd) commandPath() string {
+	path := cmd.name
+	for c := cmd.parent; c != nil; c = c.parent {
+		path = c.name + " " + path
+	}
+
+	return path
+}
+
+func (cmd *Cmd) writeManPage(w io.Writer, path string, section int) error {
+	pageName := manPageName(path)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(
+		&buf, ".TH \"%s\" \"%d\"\n", roffEscape(strings.ToUpper(pageName)), section)
+	fmt.Fprintf(&buf, ".SH NAME\n")
+	fmt.Fprintf(
+		&buf, "%s \\- %s\n", roffEscape(pageName), roffEscape(firstLine(cmd.description)))
+
+	fmt.Fprintf(&buf, ".SH SYNOPSIS\n")
+	fmt.Fprintf(&buf, ".B %s\n", roffEscape(path))
+	fmt.Fprintf(&buf, "%s\n", roffEscape(strings.TrimPrefix(cmd.usageLine(path), path + " ")))
+
+	fmt.Fprintf(&buf, ".SH DESCRIPTION\n")
+	fmt.Fprintf(&buf, "%s\n", roffEscape(cmd.description))
+
+	if len(cmd.positionals) > 0 {
+		fmt.Fprintf(&buf, ".SH ARGUMENTS\n")
+		for _, positional := range cmd.positionals {
+			fmt.Fprintf(&buf, ".TP\n")
+			fmt.Fprintf(&buf, ".I %s\n", roffEscape(positional.usage()))
+			fmt.Fprintf(&buf, "%s\n", roffEscape(positional.help))
+		}
+	}
+
+	if len(cmd.sortedSubCmdNames()) > 0 {
+		fmt.Fprintf(&buf, ".SH COMMANDS\n")
+		for _, name := range cmd.sortedSubCmdNames() {
+			fmt.Fprintf(&buf, ".TP\n")
+			fmt.Fprintf(&buf, ".B %s\n", roffEscape(name))
+			fmt.Fprintf(&buf, "%s\n", roffEscape(firstLine(cmd.subCmds[name].description)))
+		}
+	}
+
+	fmt.Fprintf(&buf, ".SH OPTIONS\n")
+	writeManOptions(&buf, cmd.visibleNamedArgs())
+
+	inheritedArgs := visibleArgs(cmd.inheritedArgs())
+	if len(inheritedArgs) > 0 {
+		fmt.Fprintf(&buf, ".SH GLOBAL OPTIONS\n")
+		writeManOptions(&buf, inheritedArgs)
+	}
+
+	if len(cmd.sortedSubCmdNames()) > 0 {
+		fmt.Fprintf(&buf, ".SH SEE ALSO\n")
+		var refs []string
+		for _, name := range cmd.sortedSubCmdNames() {
+			refs = append(
+				refs, fmt.Sprintf("\\fB%s\\fR(%d)", roffEscape(pageName + "-" + name), section))
+		}
+		fmt.Fprintf(&buf, "%s\n", strings.Join(refs, ", "))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeManOptions writes the entries of the named args |args| in a section
+// of a man page to |buf|.
+func writeManOptions(buf *bytes.Buffer, args []*NamedArg) {
+	for _, arg := range args {
+		fmt.Fprintf(buf, ".TP\n")
+		var forms []string
+		if len(arg.short) > 0 {
+			forms = append(forms, "\\fB\\-" + roffEscape(arg.short) + "\\fR")
+		}
+		forms = append(forms, "\\fB\\-\\-" + roffEscape(arg.name) + "\\fR")
+		valueSpec := ""
+		if arg.hasImplicitValue {
+			valueSpec = "[=\\fIVALUE\\fR]"
+		} else if !isBoolValue(arg.value) {
+			valueSpec = " \\fIVALUE\\fR"
+		}
+		fmt.Fprintf(buf, "%s%s\n", strings.Join(forms, ", "), valueSpec)
+		fmt.Fprintf(buf, "%s\n", roffEscape(arg.help))
+		for _, note := range arg.owner.argDocNotes(arg) {
+			fmt.Fprintf(buf, ".br\n%s\n", roffEscape(note))
+		}
+	}
+}
+
+// markdownAnchor returns the anchor of the Markdown heading |heading|.
+func markdownAnchor(heading string) string {
+	return strings.Replace(strings.ToLower(heading), " ", "-", -1)
+}
+
+// GenMarkdown writes a Markdown reference document for |cmd| and all its
+// descendant commands to |w|.
+func (cmd *Cmd) GenMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", cmd.name)
+	for _, entry := range cmd.cmdPaths() {
+		c := entry.cmd
+		fmt.Fprintf(&buf, "\n## %s\n\n", entry.path)
+		if len(c.description) > 0 {
+			fmt.Fprintf(&buf, "%s\n\n", c.description)
+		}
+
+		fmt.Fprintf(&buf, "```\n%s\n```\n", c.usageLine(entry.path))
+
+		if len(c.positionals) > 0 {
+			fmt.Fprintf(&buf, "\n### Arguments\n\n")
+			for _, positional := range c.positionals {
+				help := strings.Replace(positional.help, "\n", "\n  ", -1)
+				fmt.Fprintf(&buf, "- `%s`: %s\n", positional.usage(), help)
+			}
+		}
+
+		if len(c.sortedSubCmdNames()) > 0 {
+			fmt.Fprintf(&buf, "\n### Sub-commands\n\n")
+			for _, name := range c.sortedSubCmdNames() {
+				subPath := entry.path + " " + name
+				fmt.Fprintf(
+					&buf, "- [`%s`](#%s) - %s\n", name, markdownAnchor(subPath),
+					firstLine(c.subCmds[name].description))
+			}
+		}
+
+		fmt.Fprintf(&buf, "\n### Options\n\n")
+		writeMarkdownOptions(&buf, c.visibleNamedArgs())
+
+		inheritedArgs := visibleArgs(c.inheritedArgs())
+		if len(inheritedArgs) > 0 {
+			fmt.Fprintf(&buf, "\n### Global options\n\n")
+			writeMarkdownOptions(&buf, inheritedArgs)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeMarkdownOptions writes a list of the named args |args| to |buf|.
+func writeMarkdownOptions(buf *bytes.Buffer, args []*NamedArg) {
+	for _, arg := range args {
+		var forms []string
+		if len(arg.short) > 0 {
+			forms = append(forms, "`-" + arg.short + "`")
+		}
+		forms = append(forms, "`--" + arg.name + "`")
+		help := strings.Replace(arg.help, "\n", "\n  ", -1)
+		fmt.Fprintf(buf, "- %s: %s", strings.Join(forms, ", "), help)
+		for _, note := range arg.owner.argDocNotes(arg) {
+			fmt.Fprintf(buf, "\n  %s", note)
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+}
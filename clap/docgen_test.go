@@ -0,0 +1,98 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenManPages(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	dir, err := ioutil.TempDir("", "clap")
+	if err != nil {
+		t.Errorf("Unable to create temporary directory.\n%s", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	err = cmd.GenManPages(dir, 1)
+	if err != nil {
+		t.Errorf("Error generating man pages:\n%s", err.Error())
+		return
+	}
+
+	for _, name := range []string{"tool.1", "tool-serve.1", "tool-status.1"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("Man page '%s' not generated.", name)
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "tool.1"))
+	if err != nil {
+		t.Errorf("Unable to read man page.\n%s", err.Error())
+		return
+	}
+
+	page := string(data)
+	for _, text := range []string{
+		".TH \"TOOL\" \"1\"", "\\fB\\-\\-mode\\fR", "Allowed values: fast, slow",
+		"\\fBtool\\-serve\\fR(1)"} {
+		if !strings.Contains(page, text) {
+			t.Errorf("Man page 'tool.1' does not contain '%s'.", text)
+		}
+	}
+}
+
+func TestWriteManPageSubCmd(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	var buf bytes.Buffer
+	err := cmd.subCmds["serve"].WriteManPage(&buf, 1)
+	if err != nil {
+		t.Errorf("Error writing man page:\n%s", err.Error())
+		return
+	}
+
+	page := buf.String()
+	if !strings.Contains(page, ".TH \"TOOL\\-SERVE\" \"1\"") || !strings.Contains(page, ".B tool serve\n") {
+		t.Errorf("Man page of 'serve' is not named after its command path:\n%s", page)
+	}
+}
+
+func TestGenMarkdown(t *testing.T) {
+	cmd := createCompletionTestCmd()
+	var buf bytes.Buffer
+	err := cmd.GenMarkdown(&buf)
+	if err != nil {
+		t.Errorf("Error generating Markdown:\n%s", err.Error())
+		return
+	}
+
+	doc := buf.String()
+	for _, text := range []string{
+		"# tool\n", "## tool serve\n", "[`serve`](#tool-serve) - Serve files.",
+		"- `-m`, `--mode`: Mode.\n  Default value: fast\n  Allowed values: fast, slow\n"} {
+		if !strings.Contains(doc, text) {
+			t.Errorf("Markdown reference does not contain '%s'.", text)
+		}
+	}
+}
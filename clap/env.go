@@ -0,0 +1,89 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"os"
+	"strings"
+)
+
+// Named args can take their value from an environment variable when they are
+// not specified on the command line. The precedence of values is:
+//     command line > environment variable > default value
+
+// SetEnv sets the environment variable from which the value of |namedArg| is
+// read if it is not specified on the command line.
+func (namedArg *NamedArg) SetEnv(envVar string) *NamedArg {
+	namedArg.env = envVar
+	return namedArg
+}
+
+// SetEnvPrefix enables environment variable lookup for all named args of
+// |cmd| which do not have an environment variable set explicitly with
+// NamedArg.SetEnv. The environment variable of such an arg is |prefix|
+// followed by the upper-cased name of the arg, with '-' replaced by '_'. For
+// example, with prefix 'MYTOOL_', the variable for 'log-level' is
+// 'MYTOOL_LOG_LEVEL'. The prefix does not apply to sub-commands of |cmd|.
+func (cmd *Cmd) SetEnvPrefix(prefix string) {
+	cmd.envPrefix = prefix
+}
+
+// envVarOf returns the environment variable of |arg|, or an empty string if
+// it does not have one.
+func (cmd *Cmd) envVarOf(arg *NamedArg) string {
+	if len(arg.env) > 0 {
+		return arg.env
+	}
+	if len(cmd.envPrefix) == 0 || arg == cmd.helpArg {
+		return ""
+	}
+
+	name := strings.ToUpper(strings.Replace(arg.name, "-", "_", -1))
+	return cmd.envPrefix + name
+}
+
+// applyEnv sets the values of the named args which were not specified on the
+// command line from their environment variables.
+func (cmd *Cmd) applyEnv(ctx *parseContext) error {
+	for _, arg := range cmd.namedArgList {
+		if arg.isSet() {
+			continue
+		}
+
+		envVar := cmd.envVarOf(arg)
+		if len(envVar) == 0 {
+			continue
+		}
+
+		valStr, exists := os.LookupEnv(envVar)
+		if !exists {
+			continue
+		}
+
+		err := arg.value.Set(valStr)
+		if err != nil {
+			invalidErr := &InvalidValueError{CmdPath: ctx.path(), Name: arg.name, Err: err}
+			invalidErr.Value = valStr
+			invalidErr.Index = -1
+			invalidErr.EnvVar = envVar
+			return invalidErr
+		}
+		arg.setSource(SourceEnv, envVar)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvArgs(t *testing.T) {
+	var port int
+	var host string
+	var logLevel string
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddIntArg("port", "p", &port, 80, false, "Port.").SetEnv("TEST_CLAP_PORT")
+	cmd.AddStringArg("host", "", &host, "", true, "Host.")
+	cmd.AddStringArg("log-level", "", &logLevel, "info", false, "Log level.")
+	cmd.SetEnvPrefix("TEST_CLAP_")
+
+	os.Setenv("TEST_CLAP_PORT", "8080")
+	os.Setenv("TEST_CLAP_HOST", "localhost")
+	os.Setenv("TEST_CLAP_LOG_LEVEL", "debug")
+	defer os.Unsetenv("TEST_CLAP_PORT")
+	defer os.Unsetenv("TEST_CLAP_HOST")
+	defer os.Unsetenv("TEST_CLAP_LOG_LEVEL")
+
+	_, err := cmd.Parse([]string{"--log-level", "warning"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if port != 8080 {
+		t.Errorf("Argument 'port' has value '%d'; expecting '%d'.", port, 8080)
+	}
+	if host != "localhost" {
+		t.Errorf("Argument 'host' has value '%s'; expecting '%s'.", host, "localhost")
+	}
+	if logLevel != "warning" {
+		t.Errorf("Argument 'log-level' has value '%s'; expecting '%s'.", logLevel, "warning")
+	}
+
+	err = cmd.Clear()
+	if err != nil {
+		t.Errorf("Error clearing arg set.\n%s", err.Error())
+		return
+	}
+
+	os.Unsetenv("TEST_CLAP_HOST")
+	_, err = cmd.Parse(nil)
+	if err == nil {
+		t.Errorf("Expecting an error for missing required argument 'host'.")
+	}
+
+	cmd.Clear()
+	os.Setenv("TEST_CLAP_PORT", "eighty")
+	_, err = cmd.Parse([]string{"--host", "localhost"})
+	if err == nil {
+		t.Errorf("Expecting an error for an invalid environment variable value.")
+	}
+}
+
+func TestEnvArgsNotLookedUpWithoutPrefix(t *testing.T) {
+	var host string
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddStringArg("host", "", &host, "none", false, "Host.")
+
+	os.Setenv("HOST", "localhost")
+	defer os.Unsetenv("HOST")
+
+	_, err := cmd.Parse(nil)
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if host != "none" {
+		t.Errorf("Argument 'host' has value '%s'; expecting '%s'.", host, "none")
+	}
+}
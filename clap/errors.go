@@ -0,0 +1,185 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The errors returned by Parse for invalid command lines. They can be
+// examined with errors.As to render custom error messages or to choose exit
+// codes. In all of them, CmdPath is the list of names of the commands from
+// the root command to the command whose arguments were being parsed, and
+// Index is the index in the arguments passed to Parse of the offending
+// argument, Token. Index is -1 if the error is not caused by a specific
+// argument on the command line.
+
+// UnknownArgError is returned when a named argument is not known to the
+// command being parsed. Suggestions lists the names of the known arguments
+// which are close to Name, closest first.
+type UnknownArgError struct {
+	CmdPath []string
+	Name string
+	Token string
+	Index int
+	Suggestions []string
+}
+
+func (e *UnknownArgError) Error() string {
+	if len(e.Name) == 0 {
+		return fmt.Sprintf("Probably missing an argument name in '%s'.", e.Token)
+	}
+
+	return fmt.Sprintf("Unknown argument '%s'.%s", e.Name, suggestionText(e.Suggestions))
+}
+
+// MissingValueError is returned when a named argument which needs a value is
+// the last argument on the command line.
+type MissingValueError struct {
+	CmdPath []string
+	Name string
+	Token string
+	Index int
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("Missing value for argument '%s'.", e.Name)
+}
+
+// InvalidValueError is returned when the value of an argument cannot be
+// parsed. For values which are not from the command line, EnvVar is the
+// environment variable, or File and Line are the location in the config
+// file, from which the value was read. Err is the error returned by the
+// argument's Value.
+type InvalidValueError struct {
+	CmdPath []string
+	Name string
+	Positional bool
+	Value string
+	Token string
+	Index int
+	EnvVar string
+	File string
+	Line int
+	Err error
+}
+
+func (e *InvalidValueError) Error() string {
+	kind := "argument"
+	if e.Positional {
+		kind = "positional argument"
+	}
+
+	switch {
+	case len(e.EnvVar) > 0:
+		return fmt.Sprintf(
+			"Error parsing value of %s '%s' from environment variable '%s'.\n%s",
+			kind, e.Name, e.EnvVar, e.Err.Error())
+	case len(e.File) > 0:
+		return fmt.Sprintf(
+			"%s:%d: Error parsing value of %s '%s'.\n%s",
+			e.File, e.Line, kind, e.Name, e.Err.Error())
+	}
+
+	return fmt.Sprintf("Error parsing value of %s '%s'.\n%s", kind, e.Name, e.Err.Error())
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseFileError is returned when a response file cannot be read or
+// parsed, or when it includes itself. File is the name of the response file
+// which could not be expanded. It can be included by the response file named
+// by Token, directly or indirectly. Err describes the problem.
+type ResponseFileError struct {
+	CmdPath []string
+	File string
+	Token string
+	Index int
+	Err error
+}
+
+func (e *ResponseFileError) Error() string {
+	return fmt.Sprintf("Error expanding response file '%s'.\n%s", e.File, e.Err.Error())
+}
+
+func (e *ResponseFileError) Unwrap() error {
+	return e.Err
+}
+
+// MissingRequiredError is returned when a required named argument or
+// positional argument is not specified.
+type MissingRequiredError struct {
+	CmdPath []string
+	Name string
+	Positional bool
+}
+
+func (e *MissingRequiredError) Error() string {
+	if e.Positional {
+		return fmt.Sprintf("Required positional argument '%s' not specified.", e.Name)
+	}
+
+	return fmt.Sprintf("Required argument '%s' not specified.", e.Name)
+}
+
+// UnknownSubCmdError is returned when the first unnamed argument of a
+// command which has sub-commands, and does not declare positional
+// arguments, is not the name of one of its sub-commands but is close to the
+// name of one. Unnamed arguments which are not close to the name of any
+// sub-command are returned by Args instead. It is also returned when a name
+// following the 'help' sub-command is not known. Suggestions lists the names
+// of the sub-commands which are close to Name, closest first.
+type UnknownSubCmdError struct {
+	CmdPath []string
+	Name string
+	Token string
+	Index int
+	Suggestions []string
+}
+
+func (e *UnknownSubCmdError) Error() string {
+	return fmt.Sprintf(
+		"Unknown sub-command '%s' of command '%s'.%s",
+		e.Name, strings.Join(e.CmdPath, " "), suggestionText(e.Suggestions))
+}
+
+// ExtraArgError is returned when there are more unnamed arguments than the
+// declared positional arguments can consume.
+type ExtraArgError struct {
+	CmdPath []string
+	Token string
+	Index int
+}
+
+func (e *ExtraArgError) Error() string {
+	return fmt.Sprintf("Too many positional arguments; unexpected argument '%s'.", e.Token)
+}
+
+// ConstraintError is returned when a constraint between named arguments is
+// violated. Names lists the named arguments which violate the constraint.
+type ConstraintError struct {
+	CmdPath []string
+	Names []string
+	Reason string
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Reason
+}
@@ -0,0 +1,164 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func createErrorsTestCmd() (*Cmd, *int) {
+	var count int
+	var name string
+	var verbose bool
+	var target string
+	cmd := NewCmd("tool", "A tool.")
+	subCmd := NewCmd("run", "Run something.")
+	subCmd.AddIntArg("count", "c", &count, 1, false, "Count.")
+	subCmd.AddStringArg("name", "n", &name, "", true, "Name.")
+	subCmd.AddBoolArg("verbose", "v", &verbose, false, false, "Verbose.")
+	subCmd.AddStringPositional("target", &target, "Target.")
+	cmd.AddSubCmd(subCmd)
+	return cmd, &count
+}
+
+func TestUnknownArgError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"run", "-n", "x", "--colour", "red"})
+	var unknownErr *UnknownArgError
+	if !errors.As(err, &unknownErr) {
+		t.Errorf("Expecting an UnknownArgError; got '%v'.", err)
+		return
+	}
+	if unknownErr.Name != "colour" {
+		t.Errorf("Unknown argument name is '%s'; expecting 'colour'.", unknownErr.Name)
+	}
+	if unknownErr.Token != "--colour" || unknownErr.Index != 3 {
+		t.Errorf(
+			"Unknown argument token is '%s' at %d; expecting '--colour' at 3.",
+			unknownErr.Token, unknownErr.Index)
+	}
+	if len(unknownErr.CmdPath) != 2 || unknownErr.CmdPath[1] != "run" {
+		t.Errorf("Command path is '%s'; expecting [tool run].", unknownErr.CmdPath)
+	}
+}
+
+func TestMissingValueError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"run", "-n", "x", "t", "-count"})
+	var missingErr *MissingValueError
+	if !errors.As(err, &missingErr) {
+		t.Errorf("Expecting a MissingValueError; got '%v'.", err)
+		return
+	}
+	if missingErr.Name != "count" || missingErr.Index != 4 {
+		t.Errorf(
+			"Missing value for '%s' at %d; expecting 'count' at 4.",
+			missingErr.Name, missingErr.Index)
+	}
+}
+
+func TestInvalidValueError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"run", "-n", "x", "--count=many", "t"})
+	var invalidErr *InvalidValueError
+	if !errors.As(err, &invalidErr) {
+		t.Errorf("Expecting an InvalidValueError; got '%v'.", err)
+		return
+	}
+	if invalidErr.Name != "count" || invalidErr.Value != "many" {
+		t.Errorf(
+			"Invalid value '%s' for '%s'; expecting 'many' for 'count'.",
+			invalidErr.Value, invalidErr.Name)
+	}
+	if invalidErr.Token != "--count=many" || invalidErr.Index != 3 {
+		t.Errorf(
+			"Invalid value token is '%s' at %d; expecting '--count=many' at 3.",
+			invalidErr.Token, invalidErr.Index)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expecting the invalid value error to wrap strconv.ErrSyntax.")
+	}
+}
+
+func TestMissingRequiredError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"run", "t"})
+	var requiredErr *MissingRequiredError
+	if !errors.As(err, &requiredErr) {
+		t.Errorf("Expecting a MissingRequiredError; got '%v'.", err)
+		return
+	}
+	if requiredErr.Name != "name" || requiredErr.Positional {
+		t.Errorf("Missing required argument is '%s'; expecting 'name'.", requiredErr.Name)
+	}
+
+	cmd, _ = createErrorsTestCmd()
+	_, err = cmd.Parse([]string{"run", "-n", "x"})
+	if !errors.As(err, &requiredErr) {
+		t.Errorf("Expecting a MissingRequiredError; got '%v'.", err)
+		return
+	}
+	if requiredErr.Name != "target" || !requiredErr.Positional {
+		t.Errorf(
+			"Missing required positional argument is '%s'; expecting 'target'.",
+			requiredErr.Name)
+	}
+}
+
+func TestUnknownSubCmdError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"rum", "-n", "x"})
+	var subCmdErr *UnknownSubCmdError
+	if !errors.As(err, &subCmdErr) {
+		t.Errorf("Expecting an UnknownSubCmdError; got '%v'.", err)
+		return
+	}
+	if subCmdErr.Name != "rum" || subCmdErr.Token != "rum" || subCmdErr.Index != 0 {
+		t.Errorf(
+			"Unknown sub-command is '%s' at %d; expecting 'rum' at 0.",
+			subCmdErr.Name, subCmdErr.Index)
+	}
+
+	// Words which are not close to the name of a sub-command are unnamed
+	// arguments.
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"file.txt"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+	if len(cmd.Args()) != 1 || cmd.Args()[0] != "file.txt" {
+		t.Errorf("Expecting a single unnamed argument 'file.txt'. Found '%v'.", cmd.Args())
+	}
+}
+
+func TestExtraArgError(t *testing.T) {
+	cmd, _ := createErrorsTestCmd()
+	_, err := cmd.Parse([]string{"run", "t", "-n", "x", "u"})
+	var extraErr *ExtraArgError
+	if !errors.As(err, &extraErr) {
+		t.Errorf("Expecting an ExtraArgError; got '%v'.", err)
+		return
+	}
+	if extraErr.Token != "u" || extraErr.Index != 4 {
+		t.Errorf(
+			"Extra argument is '%s' at %d; expecting 'u' at 4.",
+			extraErr.Token, extraErr.Index)
+	}
+}
@@ -0,0 +1,236 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes returned by Execute.
+const (
+	// The command ran successfully, or help was rendered.
+	ExitOK = 0
+
+	// The Run callback, or one of the hooks, returned an error.
+	ExitFailure = 1
+
+	// The command line could not be parsed, or did not select a command
+	// which can be run.
+	ExitUsage = 2
+)
+
+// Invocation describes the command selected by the command line passed to
+// Execute.
+type Invocation struct {
+	// The commands from the root command to the selected command.
+	Cmds []*Cmd
+
+	// The selected command.
+	Cmd *Cmd
+
+	// The unnamed arguments of the selected command.
+	Args []Arg
+}
+
+// RunFunc is the type of the callbacks invoked by Execute.
+type RunFunc func(ctx context.Context, inv *Invocation) error
+
+// ExitError is an error which selects the exit code returned by Execute
+// when returned by a RunFunc. A nil Err suppresses the error message.
+type ExitError struct {
+	Code int
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("Exit code %d.", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// SetRun sets the callback which Execute invokes when the command line
+// selects |cmd|.
+func (cmd *Cmd) SetRun(run RunFunc) *Cmd {
+	cmd.run = run
+	return cmd
+}
+
+// SetPreRun sets a hook which Execute invokes before the Run callback when
+// the command line selects |cmd| or one of its sub-commands. Pre-run hooks
+// are invoked from the root command to the selected command.
+func (cmd *Cmd) SetPreRun(preRun RunFunc) *Cmd {
+	cmd.preRun = preRun
+	return cmd
+}
+
+// SetPostRun sets a hook which Execute invokes after the Run callback
+// succeeds when the command line selects |cmd| or one of its sub-commands.
+// Post-run hooks are invoked from the selected command to the root command.
+func (cmd *Cmd) SetPostRun(postRun RunFunc) *Cmd {
+	cmd.postRun = postRun
+	return cmd
+}
+
+// SetOutput sets the writers to which Execute writes help and completion
+// candidates, and errors. They default to standard output and standard
+// error. Only the writers of the command on which Execute is called are used.
+func (cmd *Cmd) SetOutput(output io.Writer, errorOutput io.Writer) *Cmd {
+	cmd.output = output
+	cmd.errorOutput = errorOutput
+	return cmd
+}
+
+// outputs returns the writers to which Execute writes its output and errors.
+func (cmd *Cmd) outputs() (io.Writer, io.Writer) {
+	var output io.Writer = os.Stdout
+	if cmd.output != nil {
+		output = cmd.output
+	}
+	var errorOutput io.Writer = os.Stderr
+	if cmd.errorOutput != nil {
+		errorOutput = cmd.errorOutput
+	}
+
+	return output, errorOutput
+}
+
+// Execute is ExecuteContext with a background context.
+func (cmd *Cmd) Execute(arguments []string) int {
+	return cmd.ExecuteContext(context.Background(), arguments)
+}
+
+// ExecuteContext parses |arguments| and invokes the Run callback of the
+// selected command, with the hooks of the commands leading to it, and
+// returns the exit code for the process. It also handles the hidden
+// completion protocol and writes help if '-h' or '--help' is specified. See
+// SetOutput for where they, and errors, are written. The arguments of |cmd|
+// and its sub-commands are cleared before parsing, so Execute can be called
+// more than once. A typical main function is:
+//
+//     os.Exit(cmd.Execute(os.Args[1:]))
+func (cmd *Cmd) ExecuteContext(ctx context.Context, arguments []string) int {
+	output, errorOutput := cmd.outputs()
+	if cmd.HandleCompletion(arguments, output) {
+		return ExitOK
+	}
+
+	err := cmd.Clear()
+	if err != nil {
+		fmt.Fprintf(errorOutput, "Error: %s\n", err.Error())
+		return ExitFailure
+	}
+
+	cmdPath, err := cmd.Parse(arguments)
+	if err != nil {
+		fmt.Fprintf(errorOutput, "Error: %s\n", err.Error())
+		return ExitUsage
+	}
+
+	inv := new(Invocation)
+	inv.Cmds = cmd.resolvePath(cmdPath)
+	inv.Cmd = inv.Cmds[len(inv.Cmds) - 1]
+	inv.Args = inv.Cmd.Args()
+
+	for _, c := range inv.Cmds {
+		if c.shouldRenderHelp {
+			err = c.WriteHelp(output)
+			if err != nil {
+				fmt.Fprintf(errorOutput, "Error: %s\n", err.Error())
+				return ExitFailure
+			}
+			return ExitOK
+		}
+	}
+
+	if inv.Cmd.run == nil {
+		if len(inv.Cmd.subCmds) > 0 {
+			fmt.Fprintf(
+				errorOutput, "Error: Missing sub-command of command '%s'.\n", inv.Cmd.name)
+		} else {
+			fmt.Fprintf(
+				errorOutput, "Error: Command '%s' cannot be run.\n", inv.Cmd.name)
+		}
+		return ExitUsage
+	}
+
+	err = inv.run(ctx)
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Err != nil {
+			fmt.Fprintf(errorOutput, "Error: %s\n", exitErr.Err.Error())
+		}
+		return exitErr.Code
+	}
+
+	fmt.Fprintf(errorOutput, "Error: %s\n", err.Error())
+	return ExitFailure
+}
+
+// resolvePath returns the commands named by |cmdPath|, as returned by Parse
+// on |cmd|.
+func (cmd *Cmd) resolvePath(cmdPath []string) []*Cmd {
+	cmds := []*Cmd{cmd}
+	for _, name := range cmdPath[1:] {
+		cmds = append(cmds, cmds[len(cmds) - 1].subCmds[name])
+	}
+
+	return cmds
+}
+
+// run invokes the pre-run hooks, the Run callback and the post-run hooks for
+// |inv|, stopping at the first error.
+func (inv *Invocation) run(ctx context.Context) error {
+	for _, c := range inv.Cmds {
+		if c.preRun != nil {
+			err := c.preRun(ctx, inv)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := inv.Cmd.run(ctx, inv)
+	if err != nil {
+		return err
+	}
+
+	for i := len(inv.Cmds) - 1; i >= 0; i-- {
+		c := inv.Cmds[i]
+		if c.postRun != nil {
+			err := c.postRun(ctx, inv)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
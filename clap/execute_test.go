@@ -0,0 +1,134 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func createExecuteTestCmd(calls *[]string, runErr error) *Cmd {
+	hook := func(name string) RunFunc {
+		return func(ctx context.Context, inv *Invocation) error {
+			*calls = append(*calls, name)
+			return nil
+		}
+	}
+
+	cmd := NewCmd("tool", "A tool.")
+	cmd.SetPreRun(hook("tool-pre")).SetPostRun(hook("tool-post"))
+	subCmd := NewCmd("sub", "A sub-command.")
+	subCmd.SetPreRun(hook("sub-pre")).SetPostRun(hook("sub-post"))
+	subCmd.SetRun(func(ctx context.Context, inv *Invocation) error {
+		*calls = append(*calls, fmt.Sprintf("sub-run %s", inv.Args))
+		return runErr
+	})
+	cmd.AddSubCmd(subCmd)
+	cmd.SetOutput(new(bytes.Buffer), new(bytes.Buffer))
+	return cmd
+}
+
+func TestExecute(t *testing.T) {
+	var calls []string
+	cmd := createExecuteTestCmd(&calls, nil)
+	code := cmd.Execute([]string{"sub", "a", "b"})
+	if code != ExitOK {
+		t.Errorf("Exit code is %d; expecting %d.", code, ExitOK)
+	}
+
+	expected := "tool-pre,sub-pre,sub-run [a b],sub-post,tool-post"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("Callbacks invoked in order '%s'; expecting '%s'.", calls, expected)
+	}
+}
+
+func TestExecuteExitCodes(t *testing.T) {
+	var calls []string
+	cmd := createExecuteTestCmd(&calls, fmt.Errorf("Failed."))
+	code := cmd.Execute([]string{"sub"})
+	if code != ExitFailure {
+		t.Errorf("Exit code is %d; expecting %d.", code, ExitFailure)
+	}
+	if strings.Join(calls, ",") != "tool-pre,sub-pre,sub-run []" {
+		t.Errorf("Post-run hooks invoked after a failed run: '%s'.", calls)
+	}
+
+	cmd = createExecuteTestCmd(&calls, &ExitError{3, nil})
+	code = cmd.Execute([]string{"sub"})
+	if code != 3 {
+		t.Errorf("Exit code is %d; expecting %d.", code, 3)
+	}
+
+	var errorOutput bytes.Buffer
+	cmd = createExecuteTestCmd(&calls, nil)
+	cmd.SetOutput(new(bytes.Buffer), &errorOutput)
+	code = cmd.Execute([]string{"sub", "--unknown"})
+	if code != ExitUsage {
+		t.Errorf("Exit code for a parse error is %d; expecting %d.", code, ExitUsage)
+	}
+	if !strings.HasPrefix(errorOutput.String(), "Error: ") || !strings.Contains(errorOutput.String(), "'unknown'") {
+		t.Errorf("Unexpected error output for a parse error: '%s'.", errorOutput.String())
+	}
+
+	errorOutput.Reset()
+	cmd = createExecuteTestCmd(&calls, nil)
+	cmd.SetOutput(new(bytes.Buffer), &errorOutput)
+	code = cmd.Execute([]string{})
+	if code != ExitUsage {
+		t.Errorf("Exit code for a missing sub-command is %d; expecting %d.", code, ExitUsage)
+	}
+	expected := "Error: Missing sub-command of command 'tool'.\n"
+	if errorOutput.String() != expected {
+		t.Errorf("Error output is '%s'; expecting '%s'.", errorOutput.String(), expected)
+	}
+}
+
+func TestExecuteTwice(t *testing.T) {
+	var calls []string
+	cmd := createExecuteTestCmd(&calls, nil)
+	cmd.Execute([]string{"sub", "a", "b"})
+	calls = nil
+	code := cmd.Execute([]string{"sub", "c"})
+	if code != ExitOK {
+		t.Errorf("Exit code is %d; expecting %d.", code, ExitOK)
+	}
+
+	expected := "tool-pre,sub-pre,sub-run [c],sub-post,tool-post"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("Callbacks invoked in order '%s'; expecting '%s'.", calls, expected)
+	}
+}
+
+func TestExecuteHelp(t *testing.T) {
+	var calls []string
+	var output bytes.Buffer
+	cmd := createExecuteTestCmd(&calls, nil)
+	cmd.SetOutput(&output, new(bytes.Buffer))
+	code := cmd.Execute([]string{"sub", "-h"})
+	if code != ExitOK {
+		t.Errorf("Exit code is %d; expecting %d.", code, ExitOK)
+	}
+	if !strings.HasPrefix(output.String(), "Usage: tool sub [options]") {
+		t.Errorf("Unexpected help output:\n%s", output.String())
+	}
+	if len(calls) != 0 {
+		t.Errorf("Callbacks invoked when rendering help: '%s'.", calls)
+	}
+}
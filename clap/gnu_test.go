@@ -0,0 +1,112 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"reflect"
+	"testing"
+)
+
+type gnuTestArgs struct {
+	extract bool
+	verbose bool
+	file string
+	offset int
+}
+
+func createGNUTestCmd(args *gnuTestArgs) *Cmd {
+	cmd := NewCmd("tar", "A test command.")
+	cmd.EnableGNUMode()
+	cmd.AddBoolArg("extract", "x", &args.extract, false, false, "Extract.")
+	cmd.AddBoolArg("verbose", "v", &args.verbose, false, false, "Verbose.")
+	cmd.AddStringArg("file", "f", &args.file, "", false, "Archive file.")
+	cmd.AddIntArg("offset", "o", &args.offset, 0, false, "Offset.")
+
+	return cmd
+}
+
+func TestGNUClusters(t *testing.T) {
+	args := new(gnuTestArgs)
+	cmd := createGNUTestCmd(args)
+	_, err := cmd.Parse([]string{"-xvf", "archive.tar", "-o-5", "true"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !args.extract || !args.verbose {
+		t.Errorf("Clustered bool arguments not set.")
+	}
+	if args.file != "archive.tar" {
+		t.Errorf("Argument 'file' has value '%s'; expecting '%s'.", args.file, "archive.tar")
+	}
+	if args.offset != -5 {
+		t.Errorf("Argument 'offset' has value '%d'; expecting '%d'.", args.offset, -5)
+	}
+	// Bool arguments do not consume the next argument in GNU mode.
+	if !reflect.DeepEqual(cmd.Args(), []Arg{"true"}) {
+		t.Errorf("Unexpected unnamed arguments '%v'.", cmd.Args())
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"-farchive.tar", "--offset", "-3", "--verbose"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.file != "archive.tar" || args.offset != -3 || !args.verbose || args.extract {
+		t.Errorf("Unexpected argument values '%v'.", *args)
+	}
+}
+
+func TestGNUTerminator(t *testing.T) {
+	args := new(gnuTestArgs)
+	cmd := createGNUTestCmd(args)
+	_, err := cmd.Parse([]string{"-v", "-", "-12", "--", "-x", "--file=a"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if args.extract || len(args.file) > 0 {
+		t.Errorf("Arguments after '--' parsed as named arguments.")
+	}
+	expected := []Arg{"-", "-12", "-x", "--file=a"}
+	if !reflect.DeepEqual(cmd.Args(), expected) {
+		t.Errorf("Unnamed arguments are '%v'; expecting '%v'.", cmd.Args(), expected)
+	}
+}
+
+func TestGNUErrors(t *testing.T) {
+	badCmdLines := [][]string{
+		[]string{"-xq"},
+		[]string{"--fil=a"},
+		[]string{"-vf"},
+		[]string{"--file"},
+		// Long names need "--" in GNU mode.
+		[]string{"-verbose"},
+	}
+	for _, cmdLine := range badCmdLines {
+		args := new(gnuTestArgs)
+		cmd := createGNUTestCmd(args)
+		_, err := cmd.Parse(cmdLine)
+		if err == nil {
+			t.Errorf("Expecting an error parsing '%v'.", cmdLine)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraints between the named args of a command are checked by Parse after
+// checking that all required args are specified. An arg satisfies a
+// constraint if it is specified on the command line, in the environment or
+// in a config file; default values do not count.
+
+type constraintKind int
+
+const (
+	// At most one of the args can be specified.
+	mutuallyExclusive = constraintKind(0)
+
+	// At least one of the args should be specified.
+	atLeastOneOf = constraintKind(1)
+
+	// If the first arg is specified, all the others should be specified.
+	requires = constraintKind(2)
+)
+
+type argConstraint struct {
+	kind constraintKind
+	args []*NamedArg
+}
+
+func (cmd *Cmd) addConstraint(kind constraintKind, names []string) error {
+	constraint := new(argConstraint)
+	constraint.kind = kind
+	for _, name := range names {
+		arg, exists := cmd.lookupLong(name)
+		if !exists {
+			return fmt.Errorf(
+				"Unknown argument '%s' in constraint of command '%s'.", name, cmd.name)
+		}
+		constraint.args = append(constraint.args, arg)
+	}
+
+	cmd.constraints = append(cmd.constraints, constraint)
+	return nil
+}
+
+// AddMutuallyExclusive declares that at most one of the named args with long
+// names |names| can be specified.
+func (cmd *Cmd) AddMutuallyExclusive(names ...string) error {
+	return cmd.addConstraint(mutuallyExclusive, names)
+}
+
+// AddAtLeastOneOf declares that at least one of the named args with long
+// names |names| should be specified.
+func (cmd *Cmd) AddAtLeastOneOf(names ...string) error {
+	return cmd.addConstraint(atLeastOneOf, names)
+}
+
+// AddRequires declares that if the named arg |name| is specified, then the
+// named args |required| should also be specified.
+func (cmd *Cmd) AddRequires(name string, required ...string) error {
+	return cmd.addConstraint(requires, append([]string{name}, required...))
+}
+
+func quotedArgNames(args []*NamedArg) string {
+	var names []string
+	for _, arg := range args {
+		names = append(names, "'" + arg.name + "'")
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func argNames(args []*NamedArg) []string {
+	var names []string
+	for _, arg := range args {
+		names = append(names, arg.name)
+	}
+
+	return names
+}
+
+func (constraint *argConstraint) check(ctx *parseContext) error {
+	var setArgs []*NamedArg
+	for _, arg := range constraint.args {
+		if arg.isSet() {
+			setArgs = append(setArgs, arg)
+		}
+	}
+
+	switch constraint.kind {
+	case mutuallyExclusive:
+		if len(setArgs) > 1 {
+			reason := fmt.Sprintf(
+				"Arguments %s are mutually exclusive and cannot be specified " +
+				"together.", quotedArgNames(setArgs))
+			return &ConstraintError{ctx.path(), argNames(setArgs), reason}
+		}
+	case atLeastOneOf:
+		if len(setArgs) == 0 {
+			reason := fmt.Sprintf(
+				"At least one of the arguments %s should be specified.",
+				quotedArgNames(constraint.args))
+			return &ConstraintError{ctx.path(), argNames(constraint.args), reason}
+		}
+	case requires:
+		if !constraint.args[0].isSet() {
+			return nil
+		}
+		var missing []*NamedArg
+		for _, arg := range constraint.args[1:] {
+			if !arg.isSet() {
+				missing = append(missing, arg)
+			}
+		}
+		if len(missing) > 0 {
+			reason := fmt.Sprintf(
+				"Argument '%s' requires %s to be specified.",
+				constraint.args[0].name, quotedArgNames(missing))
+			names := append([]string{constraint.args[0].name}, argNames(missing)...)
+			return &ConstraintError{ctx.path(), names, reason}
+		}
+	}
+
+	return nil
+}
+
+// checkConstraints checks all constraints between the named args of |cmd|.
+func (cmd *Cmd) checkConstraints(ctx *parseContext) error {
+	for _, constraint := range cmd.constraints {
+		err := constraint.check(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
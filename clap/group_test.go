@@ -0,0 +1,73 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"strings"
+	"testing"
+)
+
+func createGroupTestCmd() *Cmd {
+	var json, yaml, user, password, token string
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddStringArg("json", "", &json, "", false, "JSON output.")
+	cmd.AddStringArg("yaml", "", &yaml, "", false, "YAML output.")
+	cmd.AddStringArg("user", "u", &user, "", false, "User.")
+	cmd.AddStringArg("password", "p", &password, "", false, "Password.")
+	cmd.AddStringArg("token", "t", &token, "", false, "Token.")
+
+	cmd.AddMutuallyExclusive("json", "yaml")
+	cmd.AddAtLeastOneOf("user", "token")
+	cmd.AddRequires("user", "password")
+
+	return cmd
+}
+
+func TestConstraints(t *testing.T) {
+	validCmdLines := [][]string{
+		[]string{"--token", "t"},
+		[]string{"--json", "a", "-u", "me", "-p", "secret"},
+	}
+	for _, cmdLine := range validCmdLines {
+		cmd := createGroupTestCmd()
+		_, err := cmd.Parse(cmdLine)
+		if err != nil {
+			t.Errorf("Error while parsing '%v':\n%s", cmdLine, err.Error())
+		}
+	}
+
+	invalidCmdLines := map[string][]string{
+		"'json', 'yaml'": []string{"--json", "a", "--yaml", "b", "-t", "t"},
+		"'user', 'token'": []string{"--json", "a"},
+		"'password'": []string{"-u", "me"},
+	}
+	for names, cmdLine := range invalidCmdLines {
+		cmd := createGroupTestCmd()
+		_, err := cmd.Parse(cmdLine)
+		if err == nil || !strings.Contains(err.Error(), names) {
+			t.Errorf("Expecting an error naming %s for '%v'. Got '%v'.", names, cmdLine, err)
+		}
+	}
+}
+
+func TestConstraintUnknownArg(t *testing.T) {
+	cmd := createGroupTestCmd()
+	err := cmd.AddMutuallyExclusive("json", "xml")
+	if err == nil {
+		t.Errorf("Expecting an error for a constraint on an unknown argument.")
+	}
+}
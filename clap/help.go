@@ -0,0 +1,305 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The width to which help is wrapped if neither the command nor the COLUMNS
+// environment variable specifies one.
+const defaultHelpWidth = 80
+
+// The maximum width of the column listing the names of options, sub-commands
+// and positional arguments in help. Longer names are followed by their
+// descriptions on the next line.
+const maxHelpNameWidth = 30
+
+// An example invocation of a command, shown in its help.
+type example struct {
+	command string
+	description string
+}
+
+// SetHelpGroup puts |namedArg| in the section titled |group| of the help of
+// its command. Sections are listed in the order in which they are first
+// used, after the options which are not in any section.
+func (namedArg *NamedArg) SetHelpGroup(group string) *NamedArg {
+	namedArg.helpGroup = group
+	return namedArg
+}
+
+// AddExample adds an example invocation, |command|, of |cmd| to its help,
+// along with a |description| of what it does.
+func (cmd *Cmd) AddExample(command, description string) *Cmd {
+	cmd.examples = append(cmd.examples, example{command, description})
+	return cmd
+}
+
+// SetHelpWidth sets the width to which the help of |cmd| and its
+// sub-commands is wrapped. If it is not set, the width is read from the
+// COLUMNS environment variable, and is 80 if that is not set either.
+func (cmd *Cmd) SetHelpWidth(width int) *Cmd {
+	cmd.helpWidth = width
+	return cmd
+}
+
+func (cmd *Cmd) effectiveHelpWidth() int {
+	for c := cmd; c != nil; c = c.parent {
+		if c.helpWidth > 0 {
+			return c.helpWidth
+		}
+	}
+
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && columns > 0 {
+		return columns
+	}
+
+	return defaultHelpWidth
+}
+
+// wrapText wraps the words of every line of |text| such that the lines are
+// at most |width| characters long, unless a single word is longer.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if len(line) > 0 && len(line) + 1 + len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if len(line) > 0 {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// helpEntry is an entry of a section of help: a name, like the flags of an
+// option, and its description.
+type helpEntry struct {
+	name string
+	description string
+}
+
+// helpWriter lays out help text of a fixed width.
+type helpWriter struct {
+	buf bytes.Buffer
+	width int
+}
+
+func (hw *helpWriter) writeText(text string, indent int) {
+	padding := strings.Repeat(" ", indent)
+	for _, line := range wrapText(text, hw.width - indent) {
+		if len(line) == 0 {
+			hw.buf.WriteString("\n")
+		} else {
+			hw.buf.WriteString(padding + line + "\n")
+		}
+	}
+}
+
+// writeSection writes a section titled |title| listing |entries| in two
+// columns.
+func (hw *helpWriter) writeSection(title string, entries []helpEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	nameWidth := 0
+	for _, entry := range entries {
+		if len(entry.name) > nameWidth && len(entry.name) <= maxHelpNameWidth {
+			nameWidth = len(entry.name)
+		}
+	}
+
+	const indent = 2
+	const gap = 2
+	descIndent := indent + nameWidth + gap
+	descWidth := hw.width - descIndent
+	if descWidth < 20 {
+		descWidth = 20
+	}
+
+	hw.buf.WriteString("\n" + title + ":\n")
+	for _, entry := range entries {
+		lines := wrapText(entry.description, descWidth)
+		name := strings.Repeat(" ", indent) + entry.name
+		if len(entry.name) > nameWidth {
+			hw.buf.WriteString(name + "\n")
+		} else if len(lines) > 0 {
+			hw.buf.WriteString(name + strings.Repeat(" ", descIndent - len(name)) + lines[0] + "\n")
+			lines = lines[1:]
+		}
+		for _, line := range lines {
+			if len(line) == 0 {
+				hw.buf.WriteString("\n")
+			} else {
+				hw.buf.WriteString(strings.Repeat(" ", descIndent) + line + "\n")
+			}
+		}
+	}
+}
+
+// helpFlags returns the flags of |namedArg| as shown in help.
+func (namedArg *NamedArg) helpFlags() string {
+	flags := "    "
+	if len(namedArg.short) > 0 {
+		flags = "-" + namedArg.short + ", "
+	}
+
+	if namedArg.isNegatable() {
+		flags += "--[" + negationPrefix + "]" + namedArg.name
+	} else {
+		flags += "--" + namedArg.name
+	}
+
+	switch {
+	case namedArg.hasImplicitValue:
+		flags += "[=VALUE]"
+	case !isBoolValue(namedArg.value):
+		flags += " VALUE"
+	}
+
+	return flags
+}
+
+// helpDescription returns the help text of |namedArg| followed by notes about
+// its value.
+func (namedArg *NamedArg) helpDescription() string {
+	description := strings.TrimSpace(namedArg.help)
+	for _, note := range namedArg.owner.argDocNotes(namedArg) {
+		if len(description) > 0 {
+			description += " "
+		}
+		description += note
+		if !strings.HasSuffix(note, ".") {
+			description += "."
+		}
+	}
+
+	return description
+}
+
+func namedArgEntries(args []*NamedArg) []helpEntry {
+	var entries []helpEntry
+	for _, arg := range args {
+		entries = append(entries, helpEntry{arg.helpFlags(), arg.helpDescription()})
+	}
+
+	return entries
+}
+
+// WriteHelp writes the help of |cmd| to |w|. The help lists, in order, the
+// usage line, the description, the positional arguments, the sub-commands,
+// the help topics, the options, the options in user-defined sections, the
+// persistent options inherited from ancestors and the examples. If a help
+// topic of |cmd| was requested with the 'help' sub-command, the text of the
+// topic is written instead.
+func (cmd *Cmd) WriteHelp(w io.Writer) error {
+	if cmd.requestedTopic != nil {
+		return cmd.writeHelpTopic(w, cmd.requestedTopic)
+	}
+
+	hw := new(helpWriter)
+	hw.width = cmd.effectiveHelpWidth()
+
+	hw.writeText("Usage: " + cmd.usageLine(cmd.commandPath()), 0)
+	if len(strings.TrimSpace(cmd.description)) > 0 {
+		hw.buf.WriteString("\n")
+		hw.writeText(cmd.description, 0)
+	}
+
+	var entries []helpEntry
+	for _, positional := range cmd.positionals {
+		entries = append(entries, helpEntry{positional.usage(), positional.help})
+	}
+	hw.writeSection("Arguments", entries)
+
+	entries = nil
+	for _, name := range cmd.sortedSubCmdNames() {
+		subCmd := cmd.subCmds[name]
+		description := firstLine(subCmd.description)
+		if len(subCmd.aliases) > 0 {
+			description += " (aliases: " + strings.Join(subCmd.aliases, ", ") + ")"
+		}
+		entries = append(entries, helpEntry{name, description})
+	}
+	if cmd.hasHelpCmd() && len(cmd.subCmds) > 0 {
+		entries = append(entries, helpEntry{helpCmdName, "Print help for a command or topic."})
+	}
+	hw.writeSection("Commands", entries)
+
+	entries = nil
+	for _, topic := range cmd.helpTopics {
+		entries = append(entries, helpEntry{topic.name, topic.summary})
+	}
+	hw.writeSection("Help topics", entries)
+
+	var ungrouped []*NamedArg
+	var groups []string
+	groupArgs := make(map[string][]*NamedArg)
+	for _, arg := range cmd.visibleNamedArgs() {
+		if len(arg.helpGroup) == 0 {
+			ungrouped = append(ungrouped, arg)
+			continue
+		}
+		_, exists := groupArgs[arg.helpGroup]
+		if !exists {
+			groups = append(groups, arg.helpGroup)
+		}
+		groupArgs[arg.helpGroup] = append(groupArgs[arg.helpGroup], arg)
+	}
+	hw.writeSection("Options", namedArgEntries(ungrouped))
+	for _, group := range groups {
+		hw.writeSection(group, namedArgEntries(groupArgs[group]))
+	}
+	hw.writeSection("Global options", namedArgEntries(visibleArgs(cmd.inheritedArgs())))
+
+	if len(cmd.examples) > 0 {
+		hw.buf.WriteString("\nExamples:\n")
+		for i, ex := range cmd.examples {
+			if i > 0 {
+				hw.buf.WriteString("\n")
+			}
+			if len(ex.description) > 0 {
+				hw.writeText(ex.description, 2)
+			}
+			hw.buf.WriteString("    $ " + ex.command + "\n")
+		}
+	}
+
+	_, err := w.Write(hw.buf.Bytes())
+	return err
+}
+
+// HelpString returns the help of |cmd| as written by WriteHelp.
+func (cmd *Cmd) HelpString() string {
+	var buf bytes.Buffer
+	cmd.WriteHelp(&buf)
+	return buf.String()
+}
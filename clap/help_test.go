@@ -0,0 +1,111 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func createHelpTestCmd() (*Cmd, *Cmd) {
+	var port int
+	var name, dir string
+	var verbose, tls bool
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("verbose", "v", &verbose, false, false, "Verbose output.").SetPersistent()
+	serve := NewCmd("serve", "Serve files.\nServes the files in a directory.")
+	serve.AddIntArg("port", "p", &port, 8080, false, "Port to listen on.")
+	serve.AddStringArg("name", "n", &name, "", true, "Server name.")
+	serve.AddBoolArg("tls", "", &tls, false, false, "Enable TLS.").SetHelpGroup("Security options")
+	serve.AddStringPositional("dir", &dir, "Directory to serve.")
+	serve.AddExample("tool serve -n web /srv", "Serve /srv.")
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		cmd.AddSubCmd(NewCmd(name, "The " + name + " command."))
+	}
+	cmd.AddSubCmd(serve)
+	cmd.SetHelpWidth(60)
+	return cmd, serve
+}
+
+func TestHelpSubCmdOrder(t *testing.T) {
+	cmd, _ := createHelpTestCmd()
+	expected := "Commands:\n" +
+		"  alpha  The alpha command.\n" +
+		"  mid    The mid command.\n" +
+		"  serve  Serve files.\n" +
+		"  zeta   The zeta command.\n"
+	for i := 0; i < 10; i++ {
+		help := cmd.HelpString()
+		if !strings.Contains(help, expected) {
+			t.Errorf("Help does not list sorted sub-commands:\n%s", help)
+			return
+		}
+	}
+}
+
+func TestHelpSections(t *testing.T) {
+	_, serve := createHelpTestCmd()
+	var buf bytes.Buffer
+	err := serve.WriteHelp(&buf)
+	if err != nil {
+		t.Errorf("Error writing help.\n%s", err.Error())
+		return
+	}
+
+	help := buf.String()
+	if help != serve.HelpString() {
+		t.Errorf("WriteHelp and HelpString differ.")
+	}
+
+	sections := []string{
+		"Usage: tool serve --name VALUE [options] <dir>\n",
+		"\nArguments:\n  <dir>  Directory to serve.\n",
+		"\nOptions:\n",
+		"  -n, --name VALUE  Server name. Required argument.\n",
+		"\nSecurity options:\n      --[no-]tls  Enable TLS. Default value: false.\n",
+		"\nGlobal options:\n  -v, --[no-]verbose  Verbose output. Default value: false.\n",
+		"\nExamples:\n  Serve /srv.\n    $ tool serve -n web /srv\n",
+	}
+	last := -1
+	for _, section := range sections {
+		index := strings.Index(help, section)
+		if index < 0 || index < last {
+			t.Errorf("Help does not contain '%s' in order:\n%s", section, help)
+			return
+		}
+		last = index
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("one two three four\nfive sixsixsixsix", 9)
+	expected := []string{"one two", "three", "four", "five", "sixsixsixsix"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Text wrapped into '%q'; expecting '%q'.", lines, expected)
+	}
+
+	_, serve := createHelpTestCmd()
+	serve.AddStringArg(
+		"long", "", new(string), "", false,
+		"A long description which needs to be wrapped to the help width.")
+	for _, line := range strings.Split(serve.HelpString(), "\n") {
+		if len(line) > 60 {
+			t.Errorf("Help line '%s' is longer than the help width.", line)
+		}
+	}
+}
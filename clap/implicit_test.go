@@ -0,0 +1,70 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"testing"
+)
+
+func TestImplicitValue(t *testing.T) {
+	var color string
+	var profile string
+	var files []string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddEnumArg(
+		"color", "c", &color, "never", []string{"auto", "always"}, false, "Color.").
+		SetImplicitValue("auto")
+	cmd.AddStringArg("profile", "p", &profile, "", false, "Profile.").SetImplicitValue("cpu.prof")
+	cmd.AddStringSlicePositional("files", &files, "Files.").SetOptional()
+
+	cases := []struct {
+		gnu bool
+		arguments []string
+		color string
+		profile string
+		files int
+	}{
+		{false, []string{"a"}, "never", "", 1},
+		{false, []string{"--color", "a"}, "auto", "", 1},
+		{false, []string{"-color=always", "--profile", "a"}, "always", "cpu.prof", 1},
+		{false, []string{"--profile=mem.prof"}, "never", "mem.prof", 0},
+		{true, []string{"-c", "a"}, "auto", "", 1},
+		{true, []string{"-calways", "--profile", "a", "b"}, "always", "cpu.prof", 2},
+		{true, []string{"-pc", "a"}, "never", "c", 1},
+	}
+
+	for _, c := range cases {
+		cmd.Clear()
+		cmd.gnuMode = c.gnu
+		_, err := cmd.Parse(c.arguments)
+		if err != nil {
+			t.Errorf("Error parsing '%s'.\n%s", c.arguments, err.Error())
+			continue
+		}
+		if color != c.color || profile != c.profile || len(files) != c.files {
+			t.Errorf(
+				"Parsing '%s' gave color '%s', profile '%s' and %d files; expecting " +
+				"'%s', '%s' and %d files.", c.arguments, color, profile, len(files),
+				c.color, c.profile, c.files)
+		}
+	}
+
+	candidates := cmd.Complete([]string{"--color", "a"})
+	if len(candidates) != 0 {
+		t.Errorf("Value of an argument with an implicit value completed after a space.")
+	}
+}
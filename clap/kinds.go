@@ -0,0 +1,304 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This file implements argument kinds whose values have a richer syntax than
+// the basic numeric, bool and string kinds: durations, byte sizes, enums and
+// counters.
+
+type durationValue time.Duration
+
+func newDurationValue(p *time.Duration) *durationValue {
+	return (*durationValue)(p)
+}
+
+func (v *durationValue) Set(s string) error {
+	val, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf(
+			"Invalid duration '%s'; expecting a duration like '300ms', " +
+			"'1m30s' or '2h'.", s)
+	}
+
+	*v = durationValue(val)
+	return nil
+}
+
+func (v *durationValue) String() string {
+	return time.Duration(*v).String()
+}
+
+func (v *durationValue) Clone() Value {
+	return newDurationValue(new(time.Duration))
+}
+
+// Byte sizes are specified as an integer followed by an optional unit. Units
+// with an 'i' are powers of 1024 and the others are powers of 1000, as in
+// '64KiB' and '2G' respectively. The trailing 'B' of a unit can be omitted
+// and the case of units is ignored.
+type byteSizeUnit struct {
+	name string
+	multiplier uint64
+}
+
+// Units in decreasing order of their multipliers, binary before decimal.
+var byteSizeUnits = []byteSizeUnit{
+	{"EiB", 1 << 60},
+	{"EB", 1000000000000000000},
+	{"PiB", 1 << 50},
+	{"PB", 1000000000000000},
+	{"TiB", 1 << 40},
+	{"TB", 1000000000000},
+	{"GiB", 1 << 30},
+	{"GB", 1000000000},
+	{"MiB", 1 << 20},
+	{"MB", 1000000},
+	{"KiB", 1 << 10},
+	{"KB", 1000},
+}
+
+func parseByteSize(s string) (uint64, error) {
+	digitCount := 0
+	for digitCount < len(s) && s[digitCount] >= '0' && s[digitCount] <= '9' {
+		digitCount += 1
+	}
+	if digitCount == 0 {
+		return 0, fmt.Errorf("Missing number in byte size '%s'.", s)
+	}
+
+	num, err := strconv.ParseUint(s[:digitCount], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	unitStr := strings.TrimSpace(s[digitCount:])
+	if len(unitStr) == 0 || strings.EqualFold(unitStr, "B") {
+		return num, nil
+	}
+
+	for _, unit := range byteSizeUnits {
+		if strings.EqualFold(unitStr, unit.name) ||
+			strings.EqualFold(unitStr, strings.TrimSuffix(unit.name, "B")) {
+			if num > (^uint64(0)) / unit.multiplier {
+				return 0, fmt.Errorf("Byte size '%s' is too large.", s)
+			}
+			return num * unit.multiplier, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown unit '%s' in byte size '%s'.", unitStr, s)
+}
+
+func formatByteSize(size uint64) string {
+	if size == 0 {
+		return "0"
+	}
+
+	for _, unit := range byteSizeUnits {
+		if size % unit.multiplier == 0 {
+			return fmt.Sprintf("%d%s", size / unit.multiplier, unit.name)
+		}
+	}
+
+	return strconv.FormatUint(size, 10)
+}
+
+type byteSizeValue uint64
+
+func newByteSizeValue(p *uint64) *byteSizeValue {
+	return (*byteSizeValue)(p)
+}
+
+func (v *byteSizeValue) Set(s string) error {
+	val, err := parseByteSize(s)
+	if err != nil {
+		return fmt.Errorf(
+			"Invalid byte size '%s'; expecting a number of bytes with an " +
+			"optional unit like '512', '64KiB' or '2G'.\n%s", s, err.Error())
+	}
+
+	*v = byteSizeValue(val)
+	return nil
+}
+
+func (v *byteSizeValue) String() string {
+	return formatByteSize(uint64(*v))
+}
+
+func (v *byteSizeValue) Clone() Value {
+	return newByteSizeValue(new(uint64))
+}
+
+// choicesValue is implemented by values which can only be one of a fixed set
+// of choices.
+type choicesValue interface {
+	Value
+	choices() []string
+}
+
+type enumValue struct {
+	dest *string
+	def string
+	allowed []string
+}
+
+func newEnumValue(dest *string, def string, allowed []string) *enumValue {
+	v := new(enumValue)
+	v.dest = dest
+	v.def = def
+	v.allowed = allowed
+
+	return v
+}
+
+func (v *enumValue) Set(s string) error {
+	if s == v.def {
+		*v.dest = s
+		return nil
+	}
+
+	for _, choice := range v.allowed {
+		if s == choice {
+			*v.dest = s
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"Invalid value '%s'; expecting one of: %s.",
+		s, strings.Join(v.allowed, ", "))
+}
+
+func (v *enumValue) String() string {
+	return *v.dest
+}
+
+func (v *enumValue) Clone() Value {
+	return newEnumValue(new(string), v.def, v.allowed)
+}
+
+func (v *enumValue) choices() []string {
+	return v.allowed
+}
+
+// counterValue is the value of a counter argument. Every occurrence of the
+// argument without a value increments the count, as in '-v -v -v', and an
+// occurrence with a value sets the count, as in '--verbose=2'.
+type counterValue struct {
+	dest *int
+	def int
+
+	// Indicates whether |dest| holds a count made after the last reset.
+	counted bool
+}
+
+func newCounterValue(dest *int, def int) *counterValue {
+	v := new(counterValue)
+	v.dest = dest
+	v.def = def
+	v.counted = false
+
+	return v
+}
+
+func (v *counterValue) Set(s string) error {
+	if s == "true" {
+		if !v.counted {
+			*v.dest = 0
+		}
+		*v.dest += 1
+		v.counted = true
+		return nil
+	}
+
+	count, err := strconv.ParseInt(s, 0, 0)
+	if err != nil || count < 0 {
+		return fmt.Errorf("Invalid count '%s'; expecting a non-negative integer.", s)
+	}
+
+	*v.dest = int(count)
+	v.counted = true
+	return nil
+}
+
+func (v *counterValue) String() string {
+	return strconv.Itoa(*v.dest)
+}
+
+func (v *counterValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *counterValue) reset() {
+	*v.dest = v.def
+	v.counted = false
+}
+
+func (v *counterValue) Clone() Value {
+	return newCounterValue(new(int), v.def)
+}
+
+func isCounterValue(value Value) bool {
+	_, ok := value.(*counterValue)
+	return ok
+}
+
+func (cmd *Cmd) AddDurationArg(
+	name string, short string, dest *time.Duration, def time.Duration,
+	required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, def.String(), newDurationValue(dest), required)
+	*dest = def
+	return arg
+}
+
+// AddByteSizeArg adds an argument whose value is a size in bytes, specified
+// on the command line with an optional unit like '64KiB' or '2G'.
+func (cmd *Cmd) AddByteSizeArg(
+	name string, short string, dest *uint64, def uint64, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, formatByteSize(def), newByteSizeValue(dest), required)
+	*dest = def
+	return arg
+}
+
+// AddCounterArg adds an argument whose value is the number of times it is
+// specified on the command line, like '-v' in '-v -v -v' or '-vvv' in GNU
+// mode. The count starts from zero on the first occurrence; |def| is the
+// value if the argument is not specified.
+func (cmd *Cmd) AddCounterArg(
+	name string, short string, dest *int, def int, required bool, help string) *NamedArg {
+	arg := cmd.addNamedArg(name, short, help, strconv.Itoa(def), newCounterValue(dest, def), required)
+	*dest = def
+	return arg
+}
+
+// AddEnumArg adds a string argument whose value is restricted to one of
+// |choices|. The default value |def| need not be one of |choices|.
+func (cmd *Cmd) AddEnumArg(
+	name string, short string, dest *string, def string, choices []string,
+	required bool, help string) *NamedArg {
+	value := newEnumValue(dest, def, choices)
+	arg := cmd.addNamedArg(name, short, help, def, value, required)
+	*dest = def
+	return arg
+}
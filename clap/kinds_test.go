@@ -0,0 +1,134 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestByteSize(t *testing.T) {
+	sizes := map[string]uint64{
+		"512": 512,
+		"512B": 512,
+		"64KiB": 64 * 1024,
+		"64ki": 64 * 1024,
+		"2G": 2000000000,
+		"2gb": 2000000000,
+		"3MiB": 3 * 1024 * 1024,
+		"16EiB": 0,
+	}
+	for str, expected := range sizes {
+		size, err := parseByteSize(str)
+		if expected == 0 {
+			if err == nil {
+				t.Errorf("Expecting an error parsing byte size '%s'.", str)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing byte size '%s'.\n%s", str, err.Error())
+			continue
+		}
+		if size != expected {
+			t.Errorf("Byte size '%s' parsed as '%d'; expecting '%d'.", str, size, expected)
+		}
+		formatted, _ := parseByteSize(formatByteSize(size))
+		if formatted != size {
+			t.Errorf("Byte size '%d' does not round trip through formatting.", size)
+		}
+	}
+
+	for _, str := range []string{"", "KiB", "12XB", "-1"} {
+		_, err := parseByteSize(str)
+		if err == nil {
+			t.Errorf("Expecting an error parsing byte size '%s'.", str)
+		}
+	}
+}
+
+func TestKindArgs(t *testing.T) {
+	var timeout time.Duration
+	var bufSize uint64
+	var mode string
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddDurationArg("timeout", "t", &timeout, time.Second, false, "Timeout.")
+	cmd.AddByteSizeArg("buffer", "b", &bufSize, 4096, false, "Buffer size.")
+	cmd.AddEnumArg(
+		"mode", "m", &mode, "fast", []string{"fast", "slow"}, false, "Mode.")
+
+	_, err := cmd.Parse([]string{"-t", "1m30s", "-b=64KiB", "--mode", "slow"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if timeout != 90 * time.Second {
+		t.Errorf("Argument 'timeout' has value '%s'; expecting '1m30s'.", timeout)
+	}
+	if bufSize != 65536 {
+		t.Errorf("Argument 'buffer' has value '%d'; expecting '65536'.", bufSize)
+	}
+	if mode != "slow" {
+		t.Errorf("Argument 'mode' has value '%s'; expecting 'slow'.", mode)
+	}
+
+	err = cmd.Clear()
+	if err != nil {
+		t.Errorf("Error clearing arg set.\n%s", err.Error())
+		return
+	}
+
+	if timeout != time.Second || bufSize != 4096 || mode != "fast" {
+		t.Errorf("Arguments not reset after clearing.")
+	}
+
+	badCmdLines := map[string][]string{
+		"timeout": []string{"-t", "soon"},
+		"buffer": []string{"-b", "lots"},
+		"mode": []string{"-m", "medium"},
+	}
+	for name, cmdLine := range badCmdLines {
+		_, err = cmd.Parse(cmdLine)
+		if err == nil || !strings.Contains(err.Error(), "'" + name + "'") {
+			t.Errorf("Expecting an error naming argument '%s'. Got '%v'.", name, err)
+		}
+	}
+}
+
+func TestBindKinds(t *testing.T) {
+	type options struct {
+		Timeout time.Duration `name:"timeout" default:"5s" help:"Timeout."`
+		Color string `name:"color" default:"auto" choices:"auto,always,never" help:"Color."`
+	}
+	opts := new(options)
+	cmd, err := NewCmdFromStruct("command", "A test command.", opts)
+	if err != nil {
+		t.Errorf("Error while binding:\n%s", err.Error())
+		return
+	}
+
+	if opts.Timeout != 5 * time.Second || opts.Color != "auto" {
+		t.Errorf("Bound arguments do not have their default values.")
+	}
+
+	_, err = cmd.Parse([]string{"--color=sometimes"})
+	if err == nil {
+		t.Errorf("Expecting an error for an invalid enum value.")
+	}
+}
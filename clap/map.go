@@ -0,0 +1,255 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Map arguments fill a map with string keys from key-value pairs. Like slice
+// arguments, they can be specified multiple times, and each occurrence can
+// list multiple comma separated pairs:
+//     -D NAME=value -D DEBUG=1
+//     --label team=infra,tier=web
+// A comma separated segment without '=' continues the value of the previous
+// pair, so that '-D LIST=a,b' sets 'LIST' to 'a,b'. The pairs of all
+// occurrences accumulate in the map. The default value is replaced, not
+// added to, by the first occurrence.
+
+// How the value of map arguments is described in help and documentation.
+const mapValueFormat = "key=value[,key=value...]"
+
+func isMapValue(value Value) bool {
+	_, ok := value.(*mapValue)
+	return ok
+}
+
+// DuplicateKeyPolicy selects what happens when a key is specified more than
+// once for a map argument.
+type DuplicateKeyPolicy int
+
+const (
+	// The last value specified for a key is kept.
+	KeepLastValue = DuplicateKeyPolicy(0)
+
+	// The first value specified for a key is kept.
+	KeepFirstValue = DuplicateKeyPolicy(1)
+
+	// Specifying a key more than once is an error.
+	RejectDuplicateKeys = DuplicateKeyPolicy(2)
+)
+
+// MapValueParser parses the value of a pair of a map argument. The returned
+// value should be assignable to the element type of the map.
+type MapValueParser func(s string) (interface{}, error)
+
+// mapValue is the value of a map argument. |dest| is a pointer to a map with
+// string keys.
+type mapValue struct {
+	dest reflect.Value
+
+	// The default entries of the map.
+	def reflect.Value
+
+	parse MapValueParser
+	policy DuplicateKeyPolicy
+
+	// Keys set after the last reset.
+	setKeys map[string]bool
+}
+
+func newMapValue(dest interface{}, parse MapValueParser) (*mapValue, error) {
+	ptr := reflect.ValueOf(dest)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Map ||
+		ptr.Elem().Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf(
+			"Destination of type '%T' is not a pointer to a map with string keys.", dest)
+	}
+
+	v := new(mapValue)
+	v.dest = ptr
+	v.def = copyMap(ptr.Elem())
+	v.parse = parse
+	v.policy = KeepLastValue
+	v.setKeys = make(map[string]bool)
+
+	return v, nil
+}
+
+func copyMap(m reflect.Value) reflect.Value {
+	mapCopy := reflect.MakeMap(m.Type())
+	if m.IsNil() {
+		return mapCopy
+	}
+
+	iter := m.MapRange()
+	for iter.Next() {
+		mapCopy.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	return mapCopy
+}
+
+// splitMapValue splits |valStr| into key-value pairs.
+func splitMapValue(valStr string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, segment := range splitSliceValue(valStr) {
+		indexOfEqual := strings.Index(segment, "=")
+		if indexOfEqual < 0 {
+			if len(pairs) == 0 {
+				return nil, fmt.Errorf(
+					"Invalid key-value pair '%s'; expecting 'key=value'.", segment)
+			}
+			pairs[len(pairs) - 1][1] += sliceSep + segment
+			continue
+		}
+
+		key := segment[:indexOfEqual]
+		if len(key) == 0 {
+			return nil, fmt.Errorf("Missing key in key-value pair '%s'.", segment)
+		}
+		pairs = append(pairs, [2]string{key, segment[indexOfEqual + 1:]})
+	}
+
+	return pairs, nil
+}
+
+func (v *mapValue) Set(s string) error {
+	pairs, err := splitMapValue(s)
+	if err != nil {
+		return err
+	}
+
+	m := v.dest.Elem()
+	if len(v.setKeys) == 0 || m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	elemType := m.Type().Elem()
+	for _, pair := range pairs {
+		key, valStr := pair[0], pair[1]
+		if v.setKeys[key] {
+			if v.policy == RejectDuplicateKeys {
+				return fmt.Errorf("Key '%s' specified more than once.", key)
+			}
+			if v.policy == KeepFirstValue {
+				continue
+			}
+		}
+
+		var val interface{} = valStr
+		if v.parse != nil {
+			val, err = v.parse(valStr)
+			if err != nil {
+				return fmt.Errorf("Invalid value for key '%s'.\n%s", key, err.Error())
+			}
+		}
+
+		elem := reflect.ValueOf(val)
+		if !elem.IsValid() || !elem.Type().AssignableTo(elemType) {
+			return fmt.Errorf(
+				"Value of type '%T' for key '%s' is not assignable to '%s'.",
+				val, key, elemType)
+		}
+
+		m.SetMapIndex(reflect.ValueOf(key).Convert(m.Type().Key()), elem)
+		v.setKeys[key] = true
+	}
+
+	return nil
+}
+
+func (v *mapValue) String() string {
+	m := v.dest.Elem()
+	var keys []string
+	for _, key := range m.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		elem := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, elem.Interface()))
+	}
+
+	return joinSliceValue(pairs)
+}
+
+func (v *mapValue) reset() {
+	v.dest.Elem().Set(copyMap(v.def))
+	v.setKeys = make(map[string]bool)
+}
+
+func (v *mapValue) Clone() Value {
+	clone := new(mapValue)
+	clone.dest = reflect.New(v.dest.Type().Elem())
+	clone.def = v.def
+	clone.parse = v.parse
+	clone.policy = v.policy
+	clone.setKeys = make(map[string]bool)
+
+	return clone
+}
+
+// SetDuplicateKeyPolicy sets what happens when a key is specified more than
+// once for the map argument |namedArg|. The default policy is KeepLastValue.
+func (namedArg *NamedArg) SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) *NamedArg {
+	value, isMap := namedArg.value.(*mapValue)
+	if !isMap {
+		namedArg.owner.defErrors = append(namedArg.owner.defErrors, fmt.Errorf(
+			"Duplicate key policy set for argument '%s' of command '%s', which is " +
+			"not a map argument.", namedArg.name, namedArg.owner.name))
+		return namedArg
+	}
+
+	value.policy = policy
+	return namedArg
+}
+
+// AddStringMapArg adds a map argument which fills the map pointed to by
+// |dest|. The map |def| is the default value.
+func (cmd *Cmd) AddStringMapArg(
+	name string, short string, dest *map[string]string, def map[string]string,
+	required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.AddMapArg(name, short, dest, nil, required, help)
+}
+
+// AddMapArg adds a map argument which fills the map pointed to by |dest|,
+// which should be a pointer to a map with string keys. The values of pairs
+// are parsed with |parse|, or are used as is if |parse| is nil. The map
+// pointed to by |dest| when the argument is added is its default value. An
+// invalid |dest| is reported by Validate, Parse and ParseArgs.
+func (cmd *Cmd) AddMapArg(
+	name string, short string, dest interface{}, parse MapValueParser,
+	required bool, help string) *NamedArg {
+	value, err := newMapValue(dest, parse)
+	if err != nil {
+		cmd.defErrors = append(cmd.defErrors, fmt.Errorf(
+			"Invalid map argument '%s' of command '%s'.\n%s", name, cmd.name, err.Error()))
+		return cmd.AddValueArg(name, short, newStringValue(new(string)), required, help)
+	}
+
+	// Reset to the default so that |dest| does not share the caller's
+	// default map.
+	value.reset()
+	return cmd.addNamedArg(name, short, help, value.String(), value, required)
+}
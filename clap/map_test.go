@@ -0,0 +1,108 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestStringMapArg(t *testing.T) {
+	var defines map[string]string
+	cmd := NewCmd("cc", "A compiler.")
+	cmd.AddStringMapArg("define", "D", &defines, map[string]string{"NDEBUG": "1"}, false, "Defines.")
+
+	if defines["NDEBUG"] != "1" || len(defines) != 1 {
+		t.Errorf("Argument 'define' has value '%v'; expecting the default.", defines)
+	}
+
+	_, err := cmd.Parse([]string{"-D", "A=1", "--define=B=x=y,LIST=a,b", "-D", "A=2"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+
+	expected := map[string]string{"A": "2", "B": "x=y", "LIST": "a,b"}
+	if len(defines) != len(expected) {
+		t.Errorf("Argument 'define' has value '%v'; expecting '%v'.", defines, expected)
+	}
+	for key, val := range expected {
+		if defines[key] != val {
+			t.Errorf("Key '%s' has value '%s'; expecting '%s'.", key, defines[key], val)
+		}
+	}
+
+	cmd.Clear()
+	if len(defines) != 1 || defines["NDEBUG"] != "1" {
+		t.Errorf("Argument 'define' not reset to default; has value '%v'.", defines)
+	}
+
+	_, err = cmd.Parse([]string{"-D", "novalue"})
+	if err == nil {
+		t.Errorf("Expecting an error for a pair without '='.")
+	}
+}
+
+func TestMapArgPolicies(t *testing.T) {
+	var labels map[string]string
+	cmd := NewCmd("tool", "A tool.")
+	arg := cmd.AddStringMapArg("label", "l", &labels, nil, false, "Labels.")
+
+	arg.SetDuplicateKeyPolicy(KeepFirstValue)
+	_, err := cmd.Parse([]string{"-l", "k=1", "-l", "k=2"})
+	if err != nil || labels["k"] != "1" {
+		t.Errorf("Key 'k' has value '%s'; expecting '1'.", labels["k"])
+	}
+
+	cmd.Clear()
+	arg.SetDuplicateKeyPolicy(RejectDuplicateKeys)
+	_, err = cmd.Parse([]string{"-l", "k=1,k=2"})
+	if err == nil {
+		t.Errorf("Expecting an error for a duplicate key.")
+	}
+}
+
+func TestTypedMapArg(t *testing.T) {
+	limits := map[string]int{"cpu": 1}
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddMapArg("limit", "", &limits, func(s string) (interface{}, error) {
+		val, err := strconv.Atoi(s)
+		return val, err
+	}, false, "Limits.")
+
+	_, err := cmd.Parse([]string{"--limit", "cpu=4,mem=512"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if limits["cpu"] != 4 || limits["mem"] != 512 {
+		t.Errorf("Argument 'limit' has value '%v'; expecting cpu=4 and mem=512.", limits)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--limit", "cpu=many"})
+	if err == nil {
+		t.Errorf("Expecting an error for an invalid value.")
+	}
+
+	var notMap []string
+	bad := NewCmd("bad", "A bad command.")
+	bad.AddMapArg("bad", "", &notMap, nil, false, "Bad.")
+	if bad.Validate() == nil {
+		t.Errorf("Expecting a validation error for a map argument with a slice destination.")
+	}
+}
@@ -0,0 +1,235 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type persistentTestArgs struct {
+	verbose bool
+	logLevel string
+	force bool
+}
+
+func createPersistentTestCmd(args *persistentTestArgs) *Cmd {
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("verbose", "v", &args.verbose, false, false, "Verbose.").SetPersistent()
+	cmd.AddStringArg("log-level", "l", &args.logLevel, "info", false, "Log level.").SetPersistent()
+	subCmd := NewCmd("sub", "A sub-command.")
+	subCmd.AddBoolArg("force", "f", &args.force, false, false, "Force.")
+	cmd.AddSubCmd(subCmd)
+	return cmd
+}
+
+func TestPersistentArgs(t *testing.T) {
+	cases := [][]string{
+		{"--verbose", "sub", "--force", "-log-level", "debug"},
+		{"sub", "--verbose", "--force", "-l=debug"},
+		{"-l", "debug", "-verbose", "sub", "-f"},
+	}
+
+	for _, arguments := range cases {
+		args := new(persistentTestArgs)
+		cmd := createPersistentTestCmd(args)
+		cmdList, err := cmd.Parse(arguments)
+		if err != nil {
+			t.Errorf("Error parsing '%s'.\n%s", arguments, err.Error())
+			continue
+		}
+		if len(cmdList) != 2 || cmdList[1] != "sub" {
+			t.Errorf("Parsing '%s' processed commands '%s'; expecting [tool sub].", arguments, cmdList)
+		}
+		if !args.verbose || !args.force || args.logLevel != "debug" {
+			t.Errorf(
+				"Parsing '%s' set verbose=%t, force=%t, log-level=%s; expecting " +
+				"true, true, debug.", arguments, args.verbose, args.force, args.logLevel)
+		}
+	}
+}
+
+func TestPersistentArgsGNU(t *testing.T) {
+	args := new(persistentTestArgs)
+	cmd := createPersistentTestCmd(args)
+	cmd.EnableGNUMode()
+	_, err := cmd.Parse([]string{"sub", "-vf", "--log-level=warn"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if !args.verbose || !args.force || args.logLevel != "warn" {
+		t.Errorf("Persistent arguments not set in GNU mode.")
+	}
+}
+
+func TestPersistentArgsOverrideEnv(t *testing.T) {
+	os.Setenv("TEST_CLAP_TAGS", "a,b")
+	os.Setenv("TEST_CLAP_V", "2")
+	defer os.Unsetenv("TEST_CLAP_TAGS")
+	defer os.Unsetenv("TEST_CLAP_V")
+
+	cases := [][]string{
+		{"--tag", "c", "-v", "sub"},
+		{"sub", "--tag", "c", "-v"},
+	}
+
+	for _, arguments := range cases {
+		var tags []string
+		var verbosity int
+		cmd := NewCmd("tool", "A tool.")
+		cmd.AddStringSliceArg("tag", "", &tags, nil, false, "Tags.").
+			SetEnv("TEST_CLAP_TAGS").SetPersistent()
+		cmd.AddCounterArg("verbosity", "v", &verbosity, 0, false, "Verbosity.").
+			SetEnv("TEST_CLAP_V").SetPersistent()
+		cmd.AddSubCmd(NewCmd("sub", "A sub-command."))
+
+		_, err := cmd.Parse(arguments)
+		if err != nil {
+			t.Errorf("Error parsing '%s'.\n%s", arguments, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(tags, []string{"c"}) {
+			t.Errorf("Parsing '%s' set tags to '%v'; expecting '[c]'.", arguments, tags)
+		}
+		if verbosity != 1 {
+			t.Errorf("Parsing '%s' set verbosity to '%d'; expecting '%d'.", arguments, verbosity, 1)
+		}
+	}
+}
+
+func TestNonPersistentArgNotInherited(t *testing.T) {
+	var local int
+	args := new(persistentTestArgs)
+	cmd := createPersistentTestCmd(args)
+	cmd.AddIntArg("local", "", &local, 0, false, "Local to the root command.")
+
+	_, err := cmd.Parse([]string{"sub", "--local", "1"})
+	var unknownErr *UnknownArgError
+	if !errors.As(err, &unknownErr) || unknownErr.Name != "local" {
+		t.Errorf("Expecting an unknown argument error for 'local'; got '%v'.", err)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--local", "1", "sub"})
+	if err != nil {
+		t.Errorf("Error parsing root argument before sub-command.\n%s", err.Error())
+	}
+	if local != 1 {
+		t.Errorf("Argument 'local' has value '%d'; expecting '%d'.", local, 1)
+	}
+}
+
+func TestPersistentArgCompletion(t *testing.T) {
+	args := new(persistentTestArgs)
+	cmd := createPersistentTestCmd(args)
+	candidates := cmd.Complete([]string{"sub", "--ver"})
+	if len(candidates) != 1 || candidates[0] != "--verbose" {
+		t.Errorf("Completion candidates are '%s'; expecting [--verbose].", candidates)
+	}
+}
+
+func TestPersistentArgCompletionScripts(t *testing.T) {
+	var color string
+	args := new(persistentTestArgs)
+	cmd := createPersistentTestCmd(args)
+	cmd.AddEnumArg("color", "", &color, "auto", []string{"auto", "always", "never"}, false, "Color.").
+		SetPersistent()
+
+	generators := map[string]func(*bytes.Buffer) error{
+		"bash": func(b *bytes.Buffer) error { return cmd.GenBashCompletion(b) },
+		"zsh": func(b *bytes.Buffer) error { return cmd.GenZshCompletion(b) },
+		"fish": func(b *bytes.Buffer) error { return cmd.GenFishCompletion(b) },
+	}
+	for shell, gen := range generators {
+		var buf bytes.Buffer
+		err := gen(&buf)
+		if err != nil {
+			t.Errorf("Error generating %s completion:\n%s", shell, err.Error())
+			continue
+		}
+
+		// The values are completed for both the root command and 'sub'.
+		count := strings.Count(buf.String(), "always")
+		if count != 2 {
+			t.Errorf(
+				"The values of persistent argument 'color' are completed for %d commands " +
+				"in the %s completion script; expecting 2.", count, shell)
+		}
+	}
+}
+
+func TestAncestorChecks(t *testing.T) {
+	var json, yaml bool
+	var region, project string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("json", "", &json, false, false, "JSON output.").SetPersistent()
+	cmd.AddBoolArg("yaml", "", &yaml, false, false, "YAML output.").SetPersistent()
+	cmd.AddMutuallyExclusive("json", "yaml")
+	cmd.AddStringArg("region", "", &region, "", true, "Region.")
+	cmd.AddStringArg("project", "", &project, "", false, "Project.")
+	cmd.AddSubCmd(NewCmd("sub", "A sub-command."))
+
+	cases := [][]string{
+		{"--region", "r", "sub", "--json", "--yaml"},
+		{"--region", "r", "--json", "sub", "--yaml"},
+	}
+	for _, arguments := range cases {
+		cmd.Clear()
+		_, err := cmd.Parse(arguments)
+		var constraintErr *ConstraintError
+		if !errors.As(err, &constraintErr) {
+			t.Errorf("Expecting a constraint error parsing '%s'; got '%v'.", arguments, err)
+		}
+	}
+
+	cmd.Clear()
+	_, err := cmd.Parse([]string{"--project", "x", "sub"})
+	var requiredErr *MissingRequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Name != "region" {
+		t.Errorf("Expecting a missing required argument error for 'region'; got '%v'.", err)
+	}
+}
+
+func TestPersistentArgDocs(t *testing.T) {
+	cmd := createPersistentTestCmd(new(persistentTestArgs))
+	var page bytes.Buffer
+	err := cmd.subCmds["sub"].WriteManPage(&page, 1)
+	if err != nil {
+		t.Errorf("Error writing man page:\n%s", err.Error())
+		return
+	}
+	global := strings.Index(page.String(), ".SH GLOBAL OPTIONS\n")
+	if global < 0 || !strings.Contains(page.String()[global:], "\\fB\\-\\-verbose\\fR") {
+		t.Errorf("Man page of 'sub' does not list the inherited 'verbose' argument:\n%s", page.String())
+	}
+
+	var markdown bytes.Buffer
+	err = cmd.GenMarkdown(&markdown)
+	if err != nil {
+		t.Errorf("Error generating Markdown:\n%s", err.Error())
+		return
+	}
+	global = strings.Index(markdown.String(), "### Global options\n")
+	if global < 0 || !strings.Contains(markdown.String()[global:], "`--log-level`") {
+		t.Errorf("Markdown of 'sub' does not list the inherited 'log-level' argument:\n%s", markdown.String())
+	}
+}
@@ -0,0 +1,269 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Positional describes a positional (unnamed) argument of a command. If a
+// command declares positional arguments, Parse checks that the number of
+// unnamed arguments on the command line matches the declaration, and sets
+// the value of each positional argument from the corresponding unnamed
+// arguments. Unnamed arguments of commands which do not declare positional
+// arguments are not validated.
+//
+// A positional argument is required by default. It can be made optional,
+// and it can be made variadic to consume multiple unnamed arguments. A
+// command can have at most one variadic positional argument, but it need not
+// be the last, as in 'cp <src>... <dst>'.
+type Positional struct {
+	owner *Cmd
+	name string
+	help string
+	defValStr string
+	value Value
+	optional bool
+	variadic bool
+}
+
+// SetOptional makes |positional| optional.
+func (positional *Positional) SetOptional() *Positional {
+	positional.optional = true
+	return positional
+}
+
+// SetVariadic makes |positional| consume all unnamed arguments not consumed
+// by the other positional arguments. Its value must accumulate the values of
+// all the arguments it consumes, like the values of slice arguments do. Making
+// a second positional argument of a command variadic, or making a positional
+// argument with a value which does not accumulate variadic, is an error in
+// the definition of the command, which is reported by Parse.
+func (positional *Positional) SetVariadic() *Positional {
+	if positional.variadic {
+		return positional
+	}
+
+	cmd := positional.owner
+	for _, other := range cmd.positionals {
+		if other.variadic {
+			cmd.defErrors = append(cmd.defErrors, fmt.Errorf(
+				"Positional arguments '%s' and '%s' of command '%s' are both variadic.",
+				other.name, positional.name, cmd.name))
+		}
+	}
+	if !isRepeatedValue(positional.value) {
+		cmd.defErrors = append(cmd.defErrors, fmt.Errorf(
+			"Value of variadic positional argument '%s' of command '%s' does not " +
+			"accumulate values.", positional.name, cmd.name))
+	}
+
+	positional.variadic = true
+	return positional
+}
+
+func (positional *Positional) Name() string {
+	return positional.name
+}
+
+func (positional *Positional) Reset() error {
+	repeated, isRepeated := positional.value.(repeatedValue)
+	if isRepeated {
+		repeated.reset()
+		return nil
+	}
+
+	err := positional.value.Set(positional.defValStr)
+	if err != nil {
+		return fmt.Errorf(
+			"Error while resetting positional arg '%s' to default value.\n%s",
+			positional.name, err.Error())
+	}
+
+	return nil
+}
+
+// usage returns how |positional| is shown in a usage line.
+func (positional *Positional) usage() string {
+	switch {
+	case positional.optional && positional.variadic:
+		return "[" + positional.name + "...]"
+	case positional.optional:
+		return "[" + positional.name + "]"
+	case positional.variadic:
+		return "<" + positional.name + ">..."
+	}
+
+	return "<" + positional.name + ">"
+}
+
+// AddPositional adds a positional argument with a user defined value type.
+// The current value of |value| is the value of the positional argument if
+// it is optional and not specified on the command line.
+func (cmd *Cmd) AddPositional(name string, value Value, help string) *Positional {
+	positional := new(Positional)
+	positional.owner = cmd
+	positional.name = name
+	positional.help = help
+	positional.defValStr = value.String()
+	positional.value = value
+	positional.optional = false
+	positional.variadic = false
+
+	cmd.positionals = append(cmd.positionals, positional)
+	return positional
+}
+
+func (cmd *Cmd) AddStringPositional(name string, dest *string, help string) *Positional {
+	return cmd.AddPositional(name, newStringValue(dest), help)
+}
+
+func (cmd *Cmd) AddIntPositional(name string, dest *int, help string) *Positional {
+	return cmd.AddPositional(name, newIntValue(dest), help)
+}
+
+func (cmd *Cmd) AddUIntPositional(name string, dest *uint, help string) *Positional {
+	return cmd.AddPositional(name, newUIntValue(dest), help)
+}
+
+func (cmd *Cmd) AddFloat64Positional(name string, dest *float64, help string) *Positional {
+	return cmd.AddPositional(name, newFloat64Value(dest), help)
+}
+
+// AddStringSlicePositional adds a variadic positional argument which
+// collects the unnamed arguments it consumes into the slice pointed to by
+// |dest|. Unlike slice named args, the arguments are not split at commas.
+func (cmd *Cmd) AddStringSlicePositional(name string, dest *[]string, help string) *Positional {
+	value := newSliceValue(dest, false)
+	value.reset()
+	return cmd.AddPositional(name, value, help).SetVariadic()
+}
+
+// AddIntSlicePositional adds a variadic positional argument which collects
+// the unnamed arguments it consumes into the slice pointed to by |dest|.
+func (cmd *Cmd) AddIntSlicePositional(name string, dest *[]int, help string) *Positional {
+	value := newSliceValue(dest, false)
+	value.reset()
+	return cmd.AddPositional(name, value, help).SetVariadic()
+}
+
+// usageLine returns the usage line of |cmd| when invoked as |path|.
+// Required named args are listed before the optional ones, which are
+// represented by '[options]'.
+func (cmd *Cmd) usageLine(path string) string {
+	parts := []string{path}
+	for _, arg := range cmd.visibleNamedArgs() {
+		if arg.required && isBoolValue(arg.value) {
+			parts = append(parts, "--" + arg.name)
+		} else if arg.required {
+			parts = append(parts, "--" + arg.name + " VALUE")
+		}
+	}
+	parts = append(parts, "[options]")
+	if len(cmd.subCmds) > 0 {
+		parts = append(parts, "[command]")
+	}
+	for _, positional := range cmd.positionals {
+		parts = append(parts, positional.usage())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// assignPositionals distributes the unnamed arguments of |cmd| over its
+// positional arguments and sets their values.
+func (cmd *Cmd) assignPositionals(ctx *parseContext) error {
+	if len(cmd.positionals) == 0 {
+		return nil
+	}
+
+	var variadic *Positional
+	minCount := 0
+	optionalCount := 0
+	for _, positional := range cmd.positionals {
+		if positional.variadic {
+			variadic = positional
+		}
+		if !positional.optional {
+			minCount += 1
+		} else if !positional.variadic {
+			optionalCount += 1
+		}
+	}
+
+	argCount := len(cmd.argList)
+	if argCount < minCount {
+		// Report the first required positional argument which is not
+		// satisfied.
+		remaining := argCount
+		for _, positional := range cmd.positionals {
+			if positional.optional {
+				continue
+			}
+			if remaining == 0 {
+				return &MissingRequiredError{ctx.path(), positional.name, true}
+			}
+			remaining -= 1
+		}
+	}
+
+	// Unnamed arguments in excess of the required count are first given to
+	// the optional positional arguments in order, and the rest to the
+	// variadic positional argument.
+	extra := argCount - minCount
+	optionalExtra := extra
+	if optionalExtra > optionalCount {
+		optionalExtra = optionalCount
+	}
+	variadicExtra := extra - optionalExtra
+	if variadicExtra > 0 && variadic == nil {
+		extraIndex := argCount - variadicExtra
+		return &ExtraArgError{
+			ctx.path(), string(cmd.argList[extraIndex]), cmd.argIndices[extraIndex]}
+	}
+
+	index := 0
+	for _, positional := range cmd.positionals {
+		count := 0
+		if !positional.optional {
+			count = 1
+		}
+		if positional.variadic {
+			count += variadicExtra
+		} else if positional.optional && optionalExtra > 0 {
+			count = 1
+			optionalExtra -= 1
+		}
+
+		for j := index; j < index + count; j++ {
+			valStr := string(cmd.argList[j])
+			err := positional.value.Set(valStr)
+			if err != nil {
+				invalidErr := &InvalidValueError{CmdPath: ctx.path(), Name: positional.name, Err: err}
+				invalidErr.Positional = true
+				invalidErr.Value = valStr
+				invalidErr.Token = valStr
+				invalidErr.Index = cmd.argIndices[j]
+				return invalidErr
+			}
+		}
+		index += count
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPositionals(t *testing.T) {
+	var srcs []string
+	var dst string
+	var force bool
+	cmd := NewCmd("cp", "Copy files.")
+	cmd.AddBoolArg("force", "f", &force, false, false, "Overwrite files.")
+	cmd.AddStringSlicePositional("src", &srcs, "Source files.")
+	cmd.AddStringPositional("dst", &dst, "Destination.")
+
+	_, err := cmd.Parse([]string{"a,1", "-f", "b", "c"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(srcs, []string{"a,1", "b"}) {
+		t.Errorf("Positional 'src' has value '%v'; expecting '[a,1 b]'.", srcs)
+	}
+	if dst != "c" {
+		t.Errorf("Positional 'dst' has value '%s'; expecting '%s'.", dst, "c")
+	}
+	if len(cmd.Args()) != 3 {
+		t.Errorf("Expecting 3 unnamed arguments. Found '%v'.", cmd.Args())
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"c"})
+	if err == nil || !strings.Contains(err.Error(), "'dst'") {
+		t.Errorf("Expecting an error naming positional 'dst'. Got '%v'.", err)
+	}
+
+	if cmd.usageLine("cp") != "cp [options] <src>... <dst>" {
+		t.Errorf("Unexpected usage line '%s'.", cmd.usageLine("cp"))
+	}
+}
+
+func TestOptionalPositionals(t *testing.T) {
+	var count int
+	var ratio float64
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddIntPositional("count", &count, "Count.")
+	ratio = 0.5
+	cmd.AddFloat64Positional("ratio", &ratio, "Ratio.").SetOptional()
+
+	_, err := cmd.Parse([]string{"3"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if count != 3 || ratio != 0.5 {
+		t.Errorf("Positionals have values '%d' and '%f'; expecting '3' and '0.5'.", count, ratio)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"4", "0.25"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if count != 4 || ratio != 0.25 {
+		t.Errorf("Positionals have values '%d' and '%f'; expecting '4' and '0.25'.", count, ratio)
+	}
+
+	cmd.Clear()
+	if count != 0 || ratio != 0.5 {
+		t.Errorf("Positionals not reset after clearing.")
+	}
+
+	_, err = cmd.Parse([]string{"4", "0.25", "extra"})
+	if err == nil || !strings.Contains(err.Error(), "'extra'") {
+		t.Errorf("Expecting an error for too many positionals. Got '%v'.", err)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"four"})
+	if err == nil || !strings.Contains(err.Error(), "'count'") {
+		t.Errorf("Expecting an error naming positional 'count'. Got '%v'.", err)
+	}
+}
+
+func TestInvalidVariadicPositionals(t *testing.T) {
+	var srcs, dsts []string
+	cmd := NewCmd("cp", "Copy files.")
+	cmd.AddStringSlicePositional("src", &srcs, "Source files.")
+	cmd.AddStringSlicePositional("dst", &dsts, "Destinations.")
+
+	_, err := cmd.Parse([]string{"a"})
+	if err == nil || !strings.Contains(err.Error(), "'src' and 'dst'") {
+		t.Errorf("Expecting an error for two variadic positionals. Got '%v'.", err)
+	}
+
+	var name string
+	cmd = NewCmd("greet", "Greet people.")
+	cmd.AddPositional("name", newStringValue(&name), "Names.").SetVariadic()
+
+	_, err = cmd.Parse([]string{"a", "b"})
+	if err == nil || !strings.Contains(err.Error(), "'name'") {
+		t.Errorf("Expecting an error for a variadic positional which does not accumulate. Got '%v'.", err)
+	}
+}
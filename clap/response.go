@@ -0,0 +1,193 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// A response file holds command line arguments which are too many, or too
+// long, to be passed on the command line. When response files are enabled,
+// an argument of the form '@file' is replaced with the arguments read from
+// 'file'. The arguments in a response file are separated by whitespace and
+// are quoted like in a POSIX shell:
+//     - Characters between single quotes are taken literally.
+//     - Between double quotes, a backslash escapes '"', '\', '$', '`' and
+//       newline. Other characters are taken literally.
+//     - Outside quotes, a backslash escapes the next character.
+// Response files can refer to other response files. Relative paths are
+// relative to the current working directory. If GNU mode is enabled for the
+// root command or any of its sub-commands, arguments after the first '--'
+// are taken literally and are not expanded.
+
+// EnableResponseFiles makes Parse and ParseArgs expand '@file' arguments to
+// the arguments in 'file' when parsing the arguments of |cmd| and its
+// sub-commands. It should be called on the root command. Indices in parse
+// errors refer to the expanded arguments.
+func (cmd *Cmd) EnableResponseFiles() {
+	cmd.responseFiles = true
+}
+
+func isResponseFileArg(argument string) bool {
+	return len(argument) > 1 && strings.HasPrefix(argument, "@")
+}
+
+// expandResponseFiles returns |arguments| with the '@file' arguments replaced
+// by the arguments in the files.
+func (cmd *Cmd) expandResponseFiles(arguments []string) ([]string, error) {
+	gnuMode := false
+	for _, entry := range cmd.allCmdPaths() {
+		gnuMode = gnuMode || entry.cmd.gnuMode
+	}
+
+	var expanded []string
+	for i, argument := range arguments {
+		if gnuMode && argument == "--" {
+			expanded = append(expanded, arguments[i:]...)
+			break
+		}
+
+		fileArgs, fileErr := expandResponseFileArgs([]string{argument}, nil)
+		if fileErr != nil {
+			fileErr.CmdPath = []string{cmd.name}
+			fileErr.Token = argument
+			fileErr.Index = i
+			return nil, fileErr
+		}
+		expanded = append(expanded, fileArgs...)
+	}
+
+	return expanded, nil
+}
+
+// expandResponseFileArgs expands the '@file' arguments in |arguments|.
+// |including| lists the absolute paths of the response files being expanded,
+// outermost first. The returned error is for the response file which could
+// not be expanded; its other fields are filled in by the caller.
+func expandResponseFileArgs(
+	arguments []string, including []string) ([]string, *ResponseFileError) {
+	var expanded []string
+	for _, argument := range arguments {
+		if !isResponseFileArg(argument) {
+			expanded = append(expanded, argument)
+			continue
+		}
+
+		fileErr := new(ResponseFileError)
+		fileErr.File = argument[1:]
+		path, err := filepath.Abs(argument[1:])
+		if err != nil {
+			fileErr.Err = err
+			return nil, fileErr
+		}
+		for _, includingPath := range including {
+			if includingPath == path {
+				fileErr.Err = fmt.Errorf(
+					"The response file includes itself, via '%s'.",
+					strings.Join(including, "' -> '"))
+				return nil, fileErr
+			}
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fileErr.Err = err
+			return nil, fileErr
+		}
+
+		fileArgs, err := splitResponseFile(string(data))
+		if err != nil {
+			fileErr.Err = err
+			return nil, fileErr
+		}
+
+		fileArgs, fileErr = expandResponseFileArgs(fileArgs, append(including, path))
+		if fileErr != nil {
+			return nil, fileErr
+		}
+		expanded = append(expanded, fileArgs...)
+	}
+
+	return expanded, nil
+}
+
+// splitResponseFile splits the contents of a response file into arguments.
+func splitResponseFile(contents string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	for i := 0; i < len(contents); i++ {
+		c := contents[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case c == '\\':
+			inArg = true
+			if i + 1 < len(contents) {
+				i += 1
+				if contents[i] != '\n' {
+					current.WriteByte(contents[i])
+				}
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(contents[i + 1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("Unterminated single quote.")
+			}
+			current.WriteString(contents[i + 1:i + 1 + end])
+			i += end + 1
+		case c == '"':
+			inArg = true
+			terminated := false
+			for i += 1; i < len(contents); i++ {
+				c = contents[i]
+				if c == '"' {
+					terminated = true
+					break
+				}
+				if c == '\\' && i + 1 < len(contents) && strings.IndexByte("\"\\$`\n", contents[i + 1]) >= 0 {
+					i += 1
+					if contents[i] != '\n' {
+						current.WriteByte(contents[i])
+					}
+					continue
+				}
+				current.WriteByte(c)
+			}
+			if !terminated {
+				return nil, fmt.Errorf("Unterminated double quote.")
+			}
+		default:
+			inArg = true
+			current.WriteByte(c)
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
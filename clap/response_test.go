@@ -0,0 +1,139 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitResponseFile(t *testing.T) {
+	contents := "-I /usr/include  --name='a b'\n\"c \\\"d\\\" $e\" f\\ g\t''\n"
+	expected := []string{"-I", "/usr/include", "--name=a b", "c \"d\" $e", "f g", ""}
+	args, err := splitResponseFile(contents)
+	if err != nil {
+		t.Errorf("Error splitting response file.\n%s", err.Error())
+		return
+	}
+	if strings.Join(args, "|") != strings.Join(expected, "|") {
+		t.Errorf("Response file split into '%q'; expecting '%q'.", args, expected)
+	}
+
+	_, err = splitResponseFile("'unterminated")
+	if err == nil {
+		t.Errorf("Expecting an error for an unterminated quote.")
+	}
+}
+
+func writeResponseFile(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(contents), 0644)
+	if err != nil {
+		t.Fatalf("Error writing response file '%s'.\n%s", path, err.Error())
+	}
+
+	return path
+}
+
+func TestResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	inner := writeResponseFile(t, dir, "inner.rsp", "-o 'out file' c.o\n")
+	outer := writeResponseFile(t, dir, "outer.rsp", "a.o\n@" + inner + "\nb.o")
+
+	var output string
+	var inputs []string
+	cmd := NewCmd("ld", "A linker.")
+	cmd.AddStringArg("output", "o", &output, "a.out", false, "Output file.")
+	cmd.AddStringSlicePositional("inputs", &inputs, "Input files.")
+	cmd.EnableResponseFiles()
+
+	_, err := cmd.Parse([]string{"@" + outer, "d.o"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if output != "out file" {
+		t.Errorf("Argument 'output' has value '%s'; expecting '%s'.", output, "out file")
+	}
+	expected := "a.o c.o b.o d.o"
+	if strings.Join(inputs, " ") != expected {
+		t.Errorf("Positional argument 'inputs' has value '%s'; expecting '%s'.", inputs, expected)
+	}
+}
+
+func TestResponseFilesAfterDoubleDash(t *testing.T) {
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "inputs.rsp", "a.o b.o")
+
+	var inputs []string
+	cmd := NewCmd("ld", "A linker.")
+	cmd.AddStringSlicePositional("inputs", &inputs, "Input files.")
+	cmd.EnableResponseFiles()
+	cmd.EnableGNUMode()
+
+	_, err := cmd.Parse([]string{"@" + file, "--", "@" + file})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	expected := "a.o b.o @" + file
+	if strings.Join(inputs, " ") != expected {
+		t.Errorf("Positional argument 'inputs' has value '%s'; expecting '%s'.", inputs, expected)
+	}
+}
+
+func TestResponseFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.rsp")
+	second := writeResponseFile(t, dir, "second.rsp", "@" + first)
+	writeResponseFile(t, dir, "first.rsp", "@" + second)
+
+	var inputs []string
+	cmd := NewCmd("ld", "A linker.")
+	cmd.AddStringSlicePositional("inputs", &inputs, "Input files.")
+	cmd.EnableResponseFiles()
+
+	cmdPath, err := cmd.Parse([]string{"@" + first})
+	if err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Errorf("Expecting an error for a cyclic response file; got '%v'.", err)
+	}
+	if len(cmdPath) != 1 || cmdPath[0] != "ld" {
+		t.Errorf("Command path is '%v'; expecting '[ld]'.", cmdPath)
+	}
+
+	missing := "@" + filepath.Join(dir, "missing.rsp")
+	_, err = cmd.Parse([]string{"a.o", missing})
+	var fileErr *ResponseFileError
+	if !errors.As(err, &fileErr) {
+		t.Errorf("Expecting a ResponseFileError for a missing response file; got '%v'.", err)
+	} else if fileErr.Token != missing || fileErr.Index != 1 || !os.IsNotExist(fileErr.Err) {
+		t.Errorf(
+			"Response file error has token '%s' at %d; expecting '%s' at 1.",
+			fileErr.Token, fileErr.Index, missing)
+	}
+
+	plain := NewCmd("ld", "A linker.")
+	plain.AddStringSlicePositional("inputs", &inputs, "Input files.")
+	_, err = plain.Parse([]string{"@" + first})
+	if err != nil || len(inputs) != 1 || inputs[0] != "@" + first {
+		t.Errorf("Response files expanded without being enabled.")
+	}
+}
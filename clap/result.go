@@ -0,0 +1,423 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseArgs parses |arguments| like Parse, but leaves |cmd| untouched and
+// returns the values of the arguments in a ParseResult. The destinations
+// passed when the arguments were registered are not written to. Hence, a
+// command which is not parsed with Parse can be parsed with ParseArgs by
+// multiple goroutines concurrently. |cmd| is parsed as a root command; the
+// persistent arguments of its parent, if any, are not accepted.
+//
+// Values of arguments registered with AddValueArg or AddPositional, which
+// are not of a kind provided by this package, are parsed only if they
+// implement ClonableValue; ParseArgs returns an error otherwise. Their
+// parsed values are returned by ParseResult.Value.
+func (cmd *Cmd) ParseArgs(arguments []string) (*ParseResult, error) {
+	err := cmd.checkDefinition()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := cmd.clone(nil, make(map[*Cmd]*Cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := root.newParseContext(arguments)
+	if err != nil {
+		return nil, err
+	}
+	err = root.parse(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(ParseResult)
+	result.cmdPath = ctx.cmdPath
+	result.cmds = root.resolvePath(ctx.cmdPath)
+	result.defCmd = cmd.resolvePath(ctx.cmdPath)[len(ctx.cmdPath) - 1]
+	return result, nil
+}
+
+// ParseResult holds the values of the arguments of a command line parsed by
+// ParseArgs. Named arguments are looked up by their long names, first in the
+// selected command and then in the commands leading to it. Positional
+// arguments are looked up by name in the selected command.
+//
+// The typed getters return the zero value of their type if there is no
+// argument with the given name, or if the argument is of a different kind.
+type ParseResult struct {
+	// Names of the commands from the root command to the selected command.
+	cmdPath []string
+
+	// Private copies of the commands in |cmdPath|, which hold the values.
+	cmds []*Cmd
+
+	// The definition of the selected command.
+	defCmd *Cmd
+}
+
+// cloneValue returns a new value of the same kind as |value| for the
+// argument |name| of |cmd|.
+func (cmd *Cmd) cloneValue(name string, value Value) (Value, error) {
+	clonable, isClonable := value.(ClonableValue)
+	if !isClonable {
+		return nil, fmt.Errorf(
+			"Argument '%s' of command '%s' cannot be parsed by ParseArgs; its " +
+			"value of type '%T' does not implement ClonableValue.", name, cmd.name, value)
+	}
+
+	return clonable.Clone(), nil
+}
+
+// clone returns a copy of |cmd| and its sub-commands, in which all values
+// have destinations of their own and are reset to their defaults. |clones|
+// maps the commands cloned so far to their clones.
+func (cmd *Cmd) clone(parent *Cmd, clones map[*Cmd]*Cmd) (*Cmd, error) {
+	c := new(Cmd)
+	*c = *cmd
+	c.parent = parent
+	c.argList = nil
+	c.argIndices = nil
+	c.shouldRenderHelp = false
+	c.requestedTopic = nil
+	c.parsed = false
+	clones[cmd] = c
+
+	clonedArgs := make(map[*NamedArg]*NamedArg)
+	c.namedArgList = nil
+	for _, arg := range cmd.namedArgList {
+		clonedArg := new(NamedArg)
+		*clonedArg = *arg
+		clonedArg.owner = c
+		if arg == cmd.helpArg {
+			clonedArg.value = newBoolValue(&c.shouldRenderHelp)
+			c.helpArg = clonedArg
+		} else {
+			value, err := cmd.cloneValue(arg.name, arg.value)
+			if err != nil {
+				return nil, err
+			}
+			clonedArg.value = value
+		}
+		clonedArg.Reset()
+
+		c.namedArgList = append(c.namedArgList, clonedArg)
+		clonedArgs[arg] = clonedArg
+	}
+
+	c.namedArgMap = make(map[string]*NamedArg)
+	for key, arg := range cmd.namedArgMap {
+		c.namedArgMap[key] = clonedArgs[arg]
+	}
+
+	c.constraints = nil
+	for _, constraint := range cmd.constraints {
+		clonedConstraint := new(argConstraint)
+		clonedConstraint.kind = constraint.kind
+		for _, arg := range constraint.args {
+			clonedConstraint.args = append(clonedConstraint.args, clonedArgs[arg])
+		}
+		c.constraints = append(c.constraints, clonedConstraint)
+	}
+
+	c.positionals = nil
+	for _, positional := range cmd.positionals {
+		clonedPositional := new(Positional)
+		*clonedPositional = *positional
+		value, err := cmd.cloneValue(positional.name, positional.value)
+		if err != nil {
+			return nil, err
+		}
+		clonedPositional.value = value
+		clonedPositional.Reset()
+		c.positionals = append(c.positionals, clonedPositional)
+	}
+
+	c.subCmds = make(map[string]*Cmd)
+	for name, subCmd := range cmd.subCmds {
+		clonedSubCmd, exists := clones[subCmd]
+		if !exists {
+			var err error
+			clonedSubCmd, err = subCmd.clone(c, clones)
+			if err != nil {
+				return nil, err
+			}
+		}
+		c.subCmds[name] = clonedSubCmd
+	}
+
+	return c, nil
+}
+
+// CmdPath returns the names of the commands from the root command to the
+// selected command, like the list returned by Parse.
+func (result *ParseResult) CmdPath() []string {
+	return append([]string(nil), result.cmdPath...)
+}
+
+// Cmd returns the definition of the selected command.
+func (result *ParseResult) Cmd() *Cmd {
+	return result.defCmd
+}
+
+// ShouldRenderHelp returns true if '-h' or '--help' was specified for any of
+// the commands leading to the selected command.
+func (result *ParseResult) ShouldRenderHelp() bool {
+	for _, c := range result.cmds {
+		if c.shouldRenderHelp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HelpTopic returns the name of the help topic requested with the 'help'
+// sub-command, or an empty string if no help topic was requested.
+func (result *ParseResult) HelpTopic() string {
+	return result.cmds[len(result.cmds) - 1].HelpTopic()
+}
+
+// Positionals returns the unnamed arguments of the selected command.
+func (result *ParseResult) Positionals() []string {
+	var args []string
+	for _, arg := range result.cmds[len(result.cmds) - 1].argList {
+		args = append(args, string(arg))
+	}
+
+	return args
+}
+
+func (result *ParseResult) lookupNamedArg(name string) (*NamedArg, bool) {
+	for i := len(result.cmds) - 1; i >= 0; i-- {
+		arg, exists := result.cmds[i].namedArgMap[name]
+		if exists && arg.hasName(name) {
+			return arg, true
+		}
+	}
+
+	return nil, false
+}
+
+func (result *ParseResult) lookupValue(name string) (Value, bool) {
+	arg, exists := result.lookupNamedArg(name)
+	if exists {
+		return arg.value, true
+	}
+
+	for _, positional := range result.cmds[len(result.cmds) - 1].positionals {
+		if positional.name == name {
+			return positional.value, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsSet returns true if the named argument |name| was specified on the
+// command line, in the environment or in a config file.
+func (result *ParseResult) IsSet(name string) bool {
+	arg, exists := result.lookupNamedArg(name)
+	return exists && arg.isSet()
+}
+
+// Value returns the value of the argument |name|, or nil if there is no
+// argument with that name. For arguments registered with AddValueArg or
+// AddPositional, it is the value returned by the Clone method of the
+// registered value.
+func (result *ParseResult) Value(name string) Value {
+	value, _ := result.lookupValue(name)
+	return value
+}
+
+// String returns the value of the argument |name| formatted as a string.
+func (result *ParseResult) String(name string) string {
+	value, exists := result.lookupValue(name)
+	if !exists {
+		return ""
+	}
+
+	return value.String()
+}
+
+// Int returns the value of the int or counter argument |name|.
+func (result *ParseResult) Int(name string) int {
+	value, _ := result.lookupValue(name)
+	switch v := value.(type) {
+	case *intValue:
+		return int(*v)
+	case *counterValue:
+		return *v.dest
+	}
+
+	return 0
+}
+
+func (result *ParseResult) Int64(name string) int64 {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*int64Value)
+	if !ok {
+		return 0
+	}
+
+	return int64(*v)
+}
+
+func (result *ParseResult) UInt(name string) uint {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*uintValue)
+	if !ok {
+		return 0
+	}
+
+	return uint(*v)
+}
+
+// UInt64 returns the value of the uint64 or byte size argument |name|.
+func (result *ParseResult) UInt64(name string) uint64 {
+	value, _ := result.lookupValue(name)
+	switch v := value.(type) {
+	case *uint64Value:
+		return uint64(*v)
+	case *byteSizeValue:
+		return uint64(*v)
+	}
+
+	return 0
+}
+
+func (result *ParseResult) Float64(name string) float64 {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*float64Value)
+	if !ok {
+		return 0
+	}
+
+	return float64(*v)
+}
+
+// Bool returns the value of the bool argument |name|.
+func (result *ParseResult) Bool(name string) bool {
+	value, _ := result.lookupValue(name)
+	switch v := value.(type) {
+	case *boolValue:
+		return bool(*v)
+	}
+
+	return false
+}
+
+func (result *ParseResult) Duration(name string) time.Duration {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*durationValue)
+	if !ok {
+		return 0
+	}
+
+	return time.Duration(*v)
+}
+
+// sliceDest returns the destination of the slice argument |name|.
+func (result *ParseResult) sliceDest(name string) interface{} {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*sliceValue)
+	if !ok {
+		return nil
+	}
+
+	return v.dest
+}
+
+func (result *ParseResult) StringMap(name string) map[string]string {
+	value, _ := result.lookupValue(name)
+	v, ok := value.(*mapValue)
+	if !ok {
+		return nil
+	}
+	m, ok := v.dest.Elem().Interface().(map[string]string)
+	if !ok {
+		return nil
+	}
+
+	mapCopy := make(map[string]string)
+	for key, val := range m {
+		mapCopy[key] = val
+	}
+
+	return mapCopy
+}
+
+func (result *ParseResult) StringSlice(name string) []string {
+	dest, ok := result.sliceDest(name).(*[]string)
+	if !ok {
+		return nil
+	}
+
+	return append([]string(nil), *dest...)
+}
+
+func (result *ParseResult) IntSlice(name string) []int {
+	dest, ok := result.sliceDest(name).(*[]int)
+	if !ok {
+		return nil
+	}
+
+	return append([]int(nil), *dest...)
+}
+
+func (result *ParseResult) Int64Slice(name string) []int64 {
+	dest, ok := result.sliceDest(name).(*[]int64)
+	if !ok {
+		return nil
+	}
+
+	return append([]int64(nil), *dest...)
+}
+
+func (result *ParseResult) UIntSlice(name string) []uint {
+	dest, ok := result.sliceDest(name).(*[]uint)
+	if !ok {
+		return nil
+	}
+
+	return append([]uint(nil), *dest...)
+}
+
+func (result *ParseResult) UInt64Slice(name string) []uint64 {
+	dest, ok := result.sliceDest(name).(*[]uint64)
+	if !ok {
+		return nil
+	}
+
+	return append([]uint64(nil), *dest...)
+}
+
+func (result *ParseResult) Float64Slice(name string) []float64 {
+	dest, ok := result.sliceDest(name).(*[]float64)
+	if !ok {
+		return nil
+	}
+
+	return append([]float64(nil), *dest...)
+}
@@ -0,0 +1,160 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type resultTestDests struct {
+	port int
+	host string
+	verbose bool
+	timeout time.Duration
+	tags []string
+	file string
+}
+
+func createResultTestCmd(dests *resultTestDests) *Cmd {
+	cmd := NewCmd("server", "A server.")
+	cmd.AddBoolArg("verbose", "v", &dests.verbose, false, false, "Verbose.").SetPersistent()
+	subCmd := NewCmd("serve", "Serve.")
+	subCmd.AddIntArg("port", "p", &dests.port, 8080, false, "Port.")
+	subCmd.AddStringArg("host", "", &dests.host, "localhost", false, "Host.")
+	subCmd.AddDurationArg("timeout", "t", &dests.timeout, time.Second, false, "Timeout.")
+	subCmd.AddStringSliceArg("tag", "", &dests.tags, []string{"a"}, false, "Tags.")
+	subCmd.AddStringPositional("file", &dests.file, "File.").SetOptional()
+	cmd.AddSubCmd(subCmd)
+	return cmd
+}
+
+func TestParseArgs(t *testing.T) {
+	dests := new(resultTestDests)
+	cmd := createResultTestCmd(dests)
+	result, err := cmd.ParseArgs(
+		[]string{"serve", "-p", "9000", "--tag=x,y", "-v", "-timeout", "5s", "f.txt"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+
+	if result.Int("port") != 9000 {
+		t.Errorf("Argument 'port' has value '%d'; expecting '%d'.", result.Int("port"), 9000)
+	}
+	if result.String("host") != "localhost" {
+		t.Errorf("Argument 'host' has value '%s'; expecting '%s'.", result.String("host"), "localhost")
+	}
+	if !result.Bool("verbose") || !result.IsSet("verbose") {
+		t.Errorf("Persistent argument 'verbose' not set.")
+	}
+	if result.IsSet("host") {
+		t.Errorf("Argument 'host' reported as set.")
+	}
+	if result.Duration("timeout") != 5 * time.Second {
+		t.Errorf("Argument 'timeout' has value '%s'; expecting '5s'.", result.Duration("timeout"))
+	}
+	tags := result.StringSlice("tag")
+	if len(tags) != 2 || tags[0] != "x" || tags[1] != "y" {
+		t.Errorf("Argument 'tag' has value '%s'; expecting [x y].", tags)
+	}
+	if result.String("file") != "f.txt" {
+		t.Errorf("Positional argument 'file' has value '%s'; expecting 'f.txt'.", result.String("file"))
+	}
+	positionals := result.Positionals()
+	if len(positionals) != 1 || positionals[0] != "f.txt" {
+		t.Errorf("Positionals are '%s'; expecting [f.txt].", positionals)
+	}
+	if result.Int("host") != 0 || result.Int("missing") != 0 {
+		t.Errorf("Expecting zero values for mismatched or missing arguments.")
+	}
+	cmdPath := result.CmdPath()
+	if len(cmdPath) != 2 || cmdPath[1] != "serve" || result.Cmd().Name() != "serve" {
+		t.Errorf("Command path is '%s'; expecting [server serve].", cmdPath)
+	}
+
+	if dests.port != 8080 || dests.verbose || dests.file != "" || len(dests.tags) != 1 {
+		t.Errorf("ParseArgs modified the destinations of the command definition.")
+	}
+}
+
+func TestParseArgsConcurrent(t *testing.T) {
+	dests := new(resultTestDests)
+	cmd := createResultTestCmd(dests)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+			portStr := fmt.Sprintf("%d", port)
+			result, err := cmd.ParseArgs([]string{"serve", "--port", portStr, "--tag", portStr})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if result.Int("port") != port || result.StringSlice("tag")[0] != portStr {
+				errs <- fmt.Errorf("Result of parsing port %d has port %d.", port, result.Int("port"))
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err.Error())
+	}
+}
+
+func TestParseArgsCustomValue(t *testing.T) {
+	level := new(lowerValue)
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddValueArg("level", "l", level, false, "Log level.")
+	result, err := cmd.ParseArgs([]string{"-level", "debug"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+	if result.String("level") != "debug" {
+		t.Errorf("Argument 'level' has value '%s'; expecting 'debug'.", result.String("level"))
+	}
+	if level.val != "" {
+		t.Errorf("ParseArgs modified the user defined value.")
+	}
+	if result.Value("level").(*lowerValue).val != "debug" {
+		t.Errorf("Value of argument 'level' not returned by Value.")
+	}
+
+	_, err = cmd.ParseArgs([]string{"-level", "DEBUG"})
+	var invalidErr *InvalidValueError
+	if !errors.As(err, &invalidErr) || invalidErr.Name != "level" {
+		t.Errorf("Expecting an invalid value error for 'level'; got '%v'.", err)
+	}
+}
+
+func TestParseArgsNonClonableValue(t *testing.T) {
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddValueArg("switch", "", new(onOffValue), false, "Switch.")
+	_, err := cmd.ParseArgs([]string{"-switch", "on"})
+	if err == nil {
+		t.Errorf("Expecting an error for a value which does not implement ClonableValue.")
+	}
+}
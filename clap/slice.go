@@ -0,0 +1,208 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Slice arguments can be specified multiple times on the command line, and
+// each occurrence can list multiple comma separated values:
+//     -I a -I b
+//     --tag=x,y
+// The values of all occurrences accumulate in the destination slice. The
+// default value is replaced, not appended to, by the first occurrence.
+
+// The separator between values of a slice argument.
+const sliceSep = ","
+
+func splitSliceValue(valStr string) []string {
+	if len(valStr) == 0 {
+		return nil
+	}
+
+	return strings.Split(valStr, sliceSep)
+}
+
+func joinSliceValue(vals []string) string {
+	return strings.Join(vals, sliceSep)
+}
+
+// setSliceDest parses the comma separated values in |valStr| and appends them
+// to the slice pointed to by |dest|. If |replace| is true, the slice is
+// emptied before appending.
+func setSliceDest(dest interface{}, valStr string, replace bool) error {
+	return appendSliceDest(dest, splitSliceValue(valStr), replace)
+}
+
+// appendSliceDest parses the values in |elems| and appends them to the slice
+// pointed to by |dest|. If |replace| is true, the slice is emptied before
+// appending.
+func appendSliceDest(dest interface{}, elems []string, replace bool) error {
+	switch ptr := dest.(type) {
+	case *[]string:
+		if replace {
+			*ptr = nil
+		}
+		*ptr = append(*ptr, elems...)
+	case *[]int:
+		if replace {
+			*ptr = nil
+		}
+		for _, elem := range elems {
+			int64Val, err := strconv.ParseInt(elem, 0, 0)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, int(int64Val))
+		}
+	case *[]int64:
+		if replace {
+			*ptr = nil
+		}
+		for _, elem := range elems {
+			int64Val, err := strconv.ParseInt(elem, 0, 64)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, int64Val)
+		}
+	case *[]uint:
+		if replace {
+			*ptr = nil
+		}
+		for _, elem := range elems {
+			uint64Val, err := strconv.ParseUint(elem, 0, 0)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, uint(uint64Val))
+		}
+	case *[]uint64:
+		if replace {
+			*ptr = nil
+		}
+		for _, elem := range elems {
+			uint64Val, err := strconv.ParseUint(elem, 0, 64)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, uint64Val)
+		}
+	case *[]float64:
+		if replace {
+			*ptr = nil
+		}
+		for _, elem := range elems {
+			float64Val, err := strconv.ParseFloat(elem, 64)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, float64Val)
+		}
+	default:
+		return fmt.Errorf("Unexpected slice argument type.")
+	}
+
+	return nil
+}
+
+// formatSliceDest formats the slice pointed to by |dest| as a comma separated
+// list of values.
+func formatSliceDest(dest interface{}) string {
+	return joinSliceValue(sliceDestElems(dest))
+}
+
+// sliceDestElems formats the elements of the slice pointed to by |dest|.
+func sliceDestElems(dest interface{}) []string {
+	var vals []string
+	switch ptr := dest.(type) {
+	case *[]string:
+		vals = append(vals, *ptr...)
+	case *[]int:
+		for _, val := range *ptr {
+			vals = append(vals, strconv.FormatInt(int64(val), 10))
+		}
+	case *[]int64:
+		for _, val := range *ptr {
+			vals = append(vals, strconv.FormatInt(val, 10))
+		}
+	case *[]uint:
+		for _, val := range *ptr {
+			vals = append(vals, strconv.FormatUint(uint64(val), 10))
+		}
+	case *[]uint64:
+		for _, val := range *ptr {
+			vals = append(vals, strconv.FormatUint(val, 10))
+		}
+	case *[]float64:
+		for _, val := range *ptr {
+			vals = append(vals, strconv.FormatFloat(val, 'g', -1, 64))
+		}
+	}
+
+	return vals
+}
+
+// addSliceArg registers a slice argument whose default value is the slice
+// currently pointed to by |dest|.
+func (cmd *Cmd) addSliceArg(
+	name, short, help string, dest interface{}, required bool) *NamedArg {
+	value := newSliceValue(dest, true)
+	// Reset to the default so that |dest| does not share its backing array
+	// with the caller's default slice.
+	value.reset()
+	return cmd.addNamedArg(name, short, help, value.String(), value, required)
+}
+
+func (cmd *Cmd) AddStringSliceArg(
+	name string, short string, dest *[]string, def []string, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
+
+func (cmd *Cmd) AddIntSliceArg(
+	name string, short string, dest *[]int, def []int, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
+
+func (cmd *Cmd) AddInt64SliceArg(
+	name string, short string, dest *[]int64, def []int64, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
+
+func (cmd *Cmd) AddUIntSliceArg(
+	name string, short string, dest *[]uint, def []uint, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
+
+func (cmd *Cmd) AddUInt64SliceArg(
+	name string, short string, dest *[]uint64, def []uint64, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
+
+func (cmd *Cmd) AddFloat64SliceArg(
+	name string, short string, dest *[]float64, def []float64, required bool, help string) *NamedArg {
+	*dest = def
+	return cmd.addSliceArg(name, short, help, dest, required)
+}
@@ -0,0 +1,102 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSliceArgs(t *testing.T) {
+	var includes []string
+	var counts []int
+	var ratios []float64
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddStringSliceArg(
+		"include", "I", &includes, []string{"/usr/include"}, false, "Include paths.")
+	cmd.AddIntSliceArg("count", "c", &counts, nil, false, "Counts.")
+	cmd.AddFloat64SliceArg("ratio", "r", &ratios, []float64{0.5}, false, "Ratios.")
+
+	if !reflect.DeepEqual(includes, []string{"/usr/include"}) {
+		t.Errorf("Argument 'include' has default '%v'.", includes)
+	}
+
+	_, err := cmd.Parse([]string{"-I", "a", "--include=b,c", "-c=1,2", "-c", "3"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(includes, []string{"a", "b", "c"}) {
+		t.Errorf("Argument 'include' has value '%v'; expecting '[a b c]'.", includes)
+	}
+	if !reflect.DeepEqual(counts, []int{1, 2, 3}) {
+		t.Errorf("Argument 'count' has value '%v'; expecting '[1 2 3]'.", counts)
+	}
+	if !reflect.DeepEqual(ratios, []float64{0.5}) {
+		t.Errorf("Argument 'ratio' has value '%v'; expecting '[0.5]'.", ratios)
+	}
+
+	err = cmd.Clear()
+	if err != nil {
+		t.Errorf("Error clearing arg set.\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(includes, []string{"/usr/include"}) {
+		t.Errorf("Argument 'include' not reset after clearing. Got '%v'.", includes)
+	}
+	if len(counts) != 0 {
+		t.Errorf("Argument 'count' not reset after clearing. Got '%v'.", counts)
+	}
+
+	_, err = cmd.Parse([]string{"-c", "x"})
+	if err == nil {
+		t.Errorf("Expecting an error for an invalid slice element.")
+	}
+}
+
+func TestBindSlice(t *testing.T) {
+	type options struct {
+		Tags []string `name:"tag" default:"x,y" help:"Tags."`
+		Ids []uint64 `name:"id" help:"Identifiers."`
+	}
+	opts := new(options)
+	opts.Ids = []uint64{7}
+	cmd, err := NewCmdFromStruct("command", "A test command.", opts)
+	if err != nil {
+		t.Errorf("Error while binding:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(opts.Tags, []string{"x", "y"}) {
+		t.Errorf("Argument 'tag' has default '%v'; expecting '[x y]'.", opts.Tags)
+	}
+
+	_, err = cmd.Parse([]string{"-tag", "z"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(opts.Tags, []string{"z"}) {
+		t.Errorf("Argument 'tag' has value '%v'; expecting '[z]'.", opts.Tags)
+	}
+	if !reflect.DeepEqual(opts.Ids, []uint64{7}) {
+		t.Errorf("Argument 'id' has value '%v'; expecting '[7]'.", opts.Ids)
+	}
+}
@@ -0,0 +1,127 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"io"
+)
+
+// Source identifies where the value of a named argument came from.
+type Source int
+
+const (
+	// The argument was not specified and has its default value.
+	SourceDefault = Source(0)
+
+	// The argument was specified on the command line.
+	SourceCommandLine = Source(1)
+
+	// The value was read from the environment variable of the argument.
+	SourceEnv = Source(2)
+
+	// The value was read from a loaded config file.
+	SourceConfig = Source(3)
+)
+
+func (source Source) String() string {
+	switch source {
+	case SourceDefault:
+		return "default"
+	case SourceCommandLine:
+		return "command line"
+	case SourceEnv:
+		return "environment variable"
+	case SourceConfig:
+		return "config file"
+	}
+
+	return fmt.Sprintf("Source(%d)", int(source))
+}
+
+func (namedArg *NamedArg) setSource(source Source, origin string) {
+	namedArg.source = source
+	namedArg.origin = origin
+}
+
+// isSet returns true if the value of |namedArg| is not its default value.
+func (namedArg *NamedArg) isSet() bool {
+	return namedArg.source != SourceDefault
+}
+
+// describeSource describes where the value of |namedArg| came from, including
+// the environment variable or the location in the config file.
+func (namedArg *NamedArg) describeSource() string {
+	if len(namedArg.origin) == 0 {
+		return namedArg.source.String()
+	}
+
+	return fmt.Sprintf("%s %s", namedArg.source.String(), namedArg.origin)
+}
+
+// Source returns where the value of the named argument |name| of |cmd|, or
+// of the persistent argument |name| of its ancestors, came from during the
+// last call to Parse. It returns SourceDefault if there is no such argument.
+func (cmd *Cmd) Source(name string) Source {
+	arg, exists := cmd.lookupLong(name)
+	if !exists {
+		return SourceDefault
+	}
+
+	return arg.source
+}
+
+// IsSet returns true if the named argument |name| was specified on the
+// command line, in the environment or in a config file during the last call
+// to Parse, even if it was specified with its default value.
+func (cmd *Cmd) IsSet(name string) bool {
+	return cmd.Source(name) != SourceDefault
+}
+
+// WriteEffectiveConfig writes the current values of the named arguments
+// accepted by |cmd|, and where they came from, to |w|, one argument per
+// line:
+//
+//     port = 9000 (command line)
+//     log-level = debug (environment variable TOOL_LOG_LEVEL)
+//     cache-dir = /tmp/cache (config file tool.ini:3)
+//     timeout = 30s (default)
+func (cmd *Cmd) WriteEffectiveConfig(w io.Writer) error {
+	for _, arg := range cmd.acceptedArgs() {
+		if arg == arg.owner.helpArg {
+			continue
+		}
+
+		_, err := fmt.Fprintf(
+			w, "%s = %s (%s)\n", arg.name, arg.value.String(), arg.describeSource())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Source returns where the value of the named argument |name| came from.
+func (result *ParseResult) Source(name string) Source {
+	arg, exists := result.lookupNamedArg(name)
+	if !exists {
+		return SourceDefault
+	}
+
+	return arg.source
+}
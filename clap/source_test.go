@@ -0,0 +1,82 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	var port, workers int
+	var host, level string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddIntArg("port", "p", &port, 0, false, "Port.")
+	cmd.AddStringArg("host", "", &host, "localhost", false, "Host.")
+	cmd.AddStringArg("level", "", &level, "info", false, "Level.").SetEnv("TEST_CLAP_SOURCE_LEVEL")
+	cmd.AddIntArg("workers", "w", &workers, 4, false, "Workers.")
+
+	err := cmd.LoadINIConfig(strings.NewReader("# Workers.\nworkers = 8\n"), "tool.ini")
+	if err != nil {
+		t.Errorf("Error loading config.\n%s", err.Error())
+		return
+	}
+	os.Setenv("TEST_CLAP_SOURCE_LEVEL", "debug")
+	defer os.Unsetenv("TEST_CLAP_SOURCE_LEVEL")
+
+	_, err = cmd.Parse([]string{"--port=0"})
+	if err != nil {
+		t.Errorf("Error parsing.\n%s", err.Error())
+		return
+	}
+
+	expected := map[string]Source{
+		"port": SourceCommandLine,
+		"host": SourceDefault,
+		"level": SourceEnv,
+		"workers": SourceConfig,
+	}
+	for name, source := range expected {
+		if cmd.Source(name) != source {
+			t.Errorf("Source of '%s' is '%s'; expecting '%s'.", name, cmd.Source(name), source)
+		}
+	}
+	if !cmd.IsSet("port") || cmd.IsSet("host") {
+		t.Errorf("IsSet is not true only for explicitly specified arguments.")
+	}
+
+	var buf bytes.Buffer
+	err = cmd.WriteEffectiveConfig(&buf)
+	if err != nil {
+		t.Errorf("Error writing effective config.\n%s", err.Error())
+		return
+	}
+	expectedDump := "port = 0 (command line)\n" +
+		"host = localhost (default)\n" +
+		"level = debug (environment variable TEST_CLAP_SOURCE_LEVEL)\n" +
+		"workers = 8 (config file tool.ini:2)\n"
+	if buf.String() != expectedDump {
+		t.Errorf("Effective config is:\n%s\nexpecting:\n%s", buf.String(), expectedDump)
+	}
+
+	cmd.Clear()
+	if cmd.IsSet("port") {
+		t.Errorf("Argument 'port' is set after clearing.")
+	}
+}
@@ -0,0 +1,130 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// editDistance returns the Levenshtein distance between |a| and |b|.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b) + 1)
+	curr := make([]int, len(b) + 1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i - 1] == b[j - 1] {
+				cost = 0
+			}
+			curr[j] = prev[j - 1] + cost
+			if prev[j] + 1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j - 1] + 1 < curr[j] {
+				curr[j] = curr[j - 1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// suggest returns the names in |candidates| which are close to |name|,
+// closest first. A candidate is close if it is within an edit distance of a
+// third of the length of |name| plus one, or if |name| is a prefix of it.
+// Single character names get no suggestions.
+func suggest(name string, candidates []string) []string {
+	if len(name) < 2 {
+		return nil
+	}
+
+	maxDistance := len(name) / 3 + 1
+	distances := make(map[string]int)
+	var suggestions []string
+	for _, candidate := range candidates {
+		if _, exists := distances[candidate]; exists || candidate == name {
+			continue
+		}
+
+		distance := editDistance(name, candidate)
+		if distance <= maxDistance || strings.HasPrefix(candidate, name) {
+			distances[candidate] = distance
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		di := distances[suggestions[i]]
+		dj := distances[suggestions[j]]
+		if di != dj {
+			return di < dj
+		}
+		return suggestions[i] < suggestions[j]
+	})
+
+	return suggestions
+}
+
+// suggestionText returns the "did you mean" sentence to append to an error
+// message for |suggestions|, or an empty string if there are none.
+func suggestionText(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" Did you mean '%s'?", suggestions[0])
+	}
+
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = fmt.Sprintf("'%s'", suggestion)
+	}
+
+	return fmt.Sprintf(" Did you mean one of %s?", strings.Join(quoted, ", "))
+}
+
+// unknownArgError returns an UnknownArgError for the unknown argument |name|
+// specified by |ctx.arguments[index]|, with suggestions from the names of the
+// visible named arguments accepted by |cmd|.
+func (cmd *Cmd) unknownArgError(ctx *parseContext, name string, index int) error {
+	var names []string
+	for _, arg := range visibleArgs(cmd.acceptedArgs()) {
+		names = append(names, arg.name)
+	}
+
+	unknownErr := &UnknownArgError{ctx.path(), name, ctx.arguments[index], index, nil}
+	unknownErr.Suggestions = suggest(name, names)
+	return unknownErr
+}
+
+// unknownSubCmdError returns an UnknownSubCmdError for the unknown sub-command
+// |name| specified by |ctx.arguments[index]|, with suggestions from the names
+// of the sub-commands of |cmd|.
+func (cmd *Cmd) unknownSubCmdError(
+	ctx *parseContext, name string, index int) *UnknownSubCmdError {
+	unknownErr := &UnknownSubCmdError{ctx.path(), name, ctx.arguments[index], index, nil}
+	unknownErr.Suggestions = suggest(name, cmd.sortedSubCmdNames())
+	return unknownErr
+}
@@ -0,0 +1,97 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		distance int
+	}{
+		{"", "abc", 3},
+		{"color", "color", 0},
+		{"colr", "color", 1},
+		{"verbsoe", "verbose", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, c := range cases {
+		distance := editDistance(c.a, c.b)
+		if distance != c.distance {
+			t.Errorf(
+				"Edit distance between '%s' and '%s' is %d; expecting %d.",
+				c.a, c.b, distance, c.distance)
+		}
+	}
+}
+
+func TestSuggestUnknownArg(t *testing.T) {
+	var color string
+	var count int
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddStringArg("color", "c", &color, "", false, "Color.")
+	cmd.AddIntArg("count", "n", &count, 0, false, "Count.")
+
+	_, err := cmd.Parse([]string{"--colr", "red"})
+	var unknownErr *UnknownArgError
+	if !errors.As(err, &unknownErr) {
+		t.Errorf("Expecting an UnknownArgError; got '%v'.", err)
+		return
+	}
+	if len(unknownErr.Suggestions) != 1 || unknownErr.Suggestions[0] != "color" {
+		t.Errorf("Suggestions are '%s'; expecting [color].", unknownErr.Suggestions)
+	}
+	if !strings.Contains(err.Error(), "Did you mean 'color'?") {
+		t.Errorf("Error message '%s' does not suggest 'color'.", err.Error())
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"--zzzzzz", "red"})
+	if !errors.As(err, &unknownErr) {
+		t.Errorf("Expecting an UnknownArgError; got '%v'.", err)
+		return
+	}
+	if len(unknownErr.Suggestions) != 0 {
+		t.Errorf("Suggestions are '%s'; expecting none.", unknownErr.Suggestions)
+	}
+}
+
+func TestSuggestUnknownSubCmd(t *testing.T) {
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddSubCmd(NewCmd("status", "Show status."))
+	cmd.AddSubCmd(NewCmd("start", "Start."))
+	cmd.AddSubCmd(NewCmd("stop", "Stop."))
+
+	_, err := cmd.Parse([]string{"stat"})
+	var subCmdErr *UnknownSubCmdError
+	if !errors.As(err, &subCmdErr) {
+		t.Errorf("Expecting an UnknownSubCmdError; got '%v'.", err)
+		return
+	}
+	expected := []string{"start", "status", "stop"}
+	if strings.Join(subCmdErr.Suggestions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Suggestions are '%s'; expecting '%s'.", subCmdErr.Suggestions, expected)
+	}
+	if !strings.Contains(err.Error(), "Did you mean one of 'start', 'status', 'stop'?") {
+		t.Errorf("Error message '%s' does not list the suggestions.", err.Error())
+	}
+}
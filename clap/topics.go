@@ -0,0 +1,151 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Commands which have sub-commands or help topics accept a built-in 'help'
+// sub-command, unless they have a sub-command of their own with that name:
+//     tool help
+//     tool help sub subsub
+//     tool help environment
+// The arguments following 'help' name a path of sub-commands, optionally
+// ending in a help topic. Parse returns the path to the last of the named
+// commands, and ShouldRenderHelp of that command returns true. If the path
+// ends in a help topic, WriteHelp and RenderHelp write the text of the topic
+// instead of the help of the command.
+
+// The name of the built-in help sub-command.
+const helpCmdName = "help"
+
+// A help topic is a page of help which is not about a command, like a
+// description of the environment variables a tool reads.
+type helpTopic struct {
+	name string
+	summary string
+	text string
+}
+
+// AddHelpTopic adds a help topic named |name| to |cmd|. |summary| is listed
+// alongside |name| in the help of |cmd|, and |text| is written verbatim when
+// the topic is requested with the 'help' sub-command.
+func (cmd *Cmd) AddHelpTopic(name, summary, text string) error {
+	if len(name) == 0 || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("Invalid help topic name '%s'.", name)
+	}
+	_, exists := cmd.subCmds[name]
+	if exists || cmd.lookupHelpTopic(name) != nil {
+		return fmt.Errorf(
+			"Sub-command or help topic with name '%s' already registered with '%s'.",
+			name, cmd.name)
+	}
+
+	topic := new(helpTopic)
+	topic.name = name
+	topic.summary = summary
+	topic.text = text
+	cmd.helpTopics = append(cmd.helpTopics, topic)
+	return nil
+}
+
+func (cmd *Cmd) lookupHelpTopic(name string) *helpTopic {
+	for _, topic := range cmd.helpTopics {
+		if topic.name == name {
+			return topic
+		}
+	}
+
+	return nil
+}
+
+// hasHelpCmd returns true if |cmd| accepts the built-in 'help' sub-command.
+func (cmd *Cmd) hasHelpCmd() bool {
+	_, exists := cmd.subCmds[helpCmdName]
+	return !exists && (len(cmd.subCmds) > 0 || len(cmd.helpTopics) > 0)
+}
+
+// helpTargetNames returns the names which can follow 'help' when it is
+// used with |cmd|: the sub-commands of |cmd| followed by its help topics.
+func (cmd *Cmd) helpTargetNames() []string {
+	names := cmd.sortedSubCmdNames()
+	for _, topic := range cmd.helpTopics {
+		names = append(names, topic.name)
+	}
+
+	return names
+}
+
+// parseHelpCmd parses the arguments of the built-in 'help' sub-command of
+// |cmd| starting at |ctx.arguments[start]|.
+func (cmd *Cmd) parseHelpCmd(ctx *parseContext, start int) error {
+	target := cmd
+	for i := start; i < len(ctx.arguments); i++ {
+		name := ctx.arguments[i]
+		subCmd, exists := target.subCmds[name]
+		if exists {
+			target = subCmd
+			ctx.cmdPath = append(ctx.cmdPath, subCmd.name)
+			continue
+		}
+
+		topic := target.lookupHelpTopic(name)
+		if topic == nil || i != len(ctx.arguments) - 1 {
+			unknownErr := &UnknownSubCmdError{ctx.path(), name, name, i, nil}
+			unknownErr.Suggestions = suggest(name, target.helpTargetNames())
+			return unknownErr
+		}
+		target.requestedTopic = topic
+	}
+
+	target.shouldRenderHelp = true
+	return nil
+}
+
+func (cmd *Cmd) writeHelpTopic(w io.Writer, topic *helpTopic) error {
+	text := topic.text
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+// WriteHelpTopic writes the text of the help topic |name| of |cmd| to |w|.
+func (cmd *Cmd) WriteHelpTopic(w io.Writer, name string) error {
+	topic := cmd.lookupHelpTopic(name)
+	if topic == nil {
+		return fmt.Errorf("No help topic named '%s' in command '%s'.", name, cmd.name)
+	}
+
+	return cmd.writeHelpTopic(w, topic)
+}
+
+// HelpTopic returns the name of the help topic requested with the 'help'
+// sub-command during the last call to Parse, or an empty string if no help
+// topic was requested.
+func (cmd *Cmd) HelpTopic() string {
+	if cmd.requestedTopic == nil {
+		return ""
+	}
+
+	return cmd.requestedTopic.name
+}
@@ -0,0 +1,180 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const environmentTopic = "Environment variables:\n  TOOL_HOME    The home directory.\n"
+
+func createTopicsTestCmd() *Cmd {
+	cmd, _ := createHelpTestCmd()
+	cmd.AddHelpTopic("environment", "Environment variables read by tool.", environmentTopic)
+	return cmd
+}
+
+func TestHelpCmd(t *testing.T) {
+	cmd := createTopicsTestCmd()
+	cmdPath, err := cmd.Parse([]string{"help", "serve"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(cmdPath, []string{"tool", "serve"}) {
+		t.Errorf("Command path is '%v'; expecting '[tool serve]'.", cmdPath)
+	}
+	serve := cmd.subCmds["serve"]
+	if !serve.ShouldRenderHelp() {
+		t.Errorf("Help of 'serve' not requested.")
+	}
+	if cmd.ShouldRenderHelp() {
+		t.Errorf("Help of 'tool' requested.")
+	}
+	if !strings.HasPrefix(serve.HelpString(), "Usage: tool serve") {
+		t.Errorf("Unexpected help of 'serve':\n%s", serve.HelpString())
+	}
+
+	cmd.Clear()
+	cmdPath, err = cmd.Parse([]string{"help"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+	if len(cmdPath) != 1 || !cmd.ShouldRenderHelp() {
+		t.Errorf("Help of 'tool' not requested by 'help'.")
+	}
+}
+
+func TestHelpCmdNested(t *testing.T) {
+	cmd := NewCmd("tool", "A tool.")
+	sub := NewCmd("sub", "A sub-command.")
+	subsub := NewCmd("subsub", "A nested sub-command.")
+	sub.AddSubCmd(subsub)
+	cmd.AddSubCmd(sub)
+
+	result, err := cmd.ParseArgs([]string{"help", "sub", "subsub"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(result.CmdPath(), []string{"tool", "sub", "subsub"}) {
+		t.Errorf("Command path is '%v'; expecting '[tool sub subsub]'.", result.CmdPath())
+	}
+	if !result.ShouldRenderHelp() {
+		t.Errorf("Help of 'subsub' not requested.")
+	}
+	if subsub.ShouldRenderHelp() {
+		t.Errorf("ParseArgs modified the command definition.")
+	}
+
+	// 'help' is a sub-command only where a sub-command is expected.
+	_, err = cmd.ParseArgs([]string{"sub", "help", "subsub"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+	}
+}
+
+func TestHelpTopic(t *testing.T) {
+	cmd := createTopicsTestCmd()
+	if !strings.Contains(cmd.HelpString(), "Help topics:\n  environment  Environment variables read by tool.\n") {
+		t.Errorf("Help does not list help topics:\n%s", cmd.HelpString())
+	}
+	if !strings.Contains(cmd.HelpString(), "  help   Print help for a command or topic.\n") {
+		t.Errorf("Help does not list the 'help' sub-command:\n%s", cmd.HelpString())
+	}
+
+	_, err := cmd.Parse([]string{"help", "environment"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+	if cmd.HelpTopic() != "environment" {
+		t.Errorf("Help topic is '%s'; expecting 'environment'.", cmd.HelpTopic())
+	}
+
+	var buf bytes.Buffer
+	cmd.WriteHelp(&buf)
+	if buf.String() != environmentTopic {
+		t.Errorf("Unexpected help topic output:\n%s", buf.String())
+	}
+
+	cmd.Clear()
+	if cmd.HelpTopic() != "" || strings.HasPrefix(cmd.HelpString(), "Environment") {
+		t.Errorf("Help topic not cleared.")
+	}
+
+	err = cmd.AddHelpTopic("serve", "Serving.", "Serving files.")
+	if err == nil {
+		t.Errorf("Expecting an error for a help topic with the name of a sub-command.")
+	}
+}
+
+func TestHelpCmdUnknown(t *testing.T) {
+	cmd := createTopicsTestCmd()
+	_, err := cmd.Parse([]string{"help", "enviroment"})
+	unknownErr, isUnknown := err.(*UnknownSubCmdError)
+	if !isUnknown {
+		t.Errorf("Expecting an UnknownSubCmdError; got '%v'.", err)
+		return
+	}
+	if unknownErr.Index != 1 || !reflect.DeepEqual(unknownErr.Suggestions, []string{"environment"}) {
+		t.Errorf(
+			"Unexpected index '%d' or suggestions '%v'.", unknownErr.Index, unknownErr.Suggestions)
+	}
+
+	cmd.Clear()
+	_, err = cmd.Parse([]string{"help", "environment", "serve"})
+	if err == nil {
+		t.Errorf("Expecting an error for arguments following a help topic.")
+	}
+}
+
+func TestHelpCmdOverridden(t *testing.T) {
+	var topic string
+	cmd := NewCmd("tool", "A tool.")
+	help := NewCmd("help", "Custom help.")
+	help.AddStringPositional("topic", &topic, "Topic.")
+	cmd.AddSubCmd(help)
+
+	cmdPath, err := cmd.Parse([]string{"help", "anything"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+	if len(cmdPath) != 2 || topic != "anything" {
+		t.Errorf("Custom 'help' sub-command not used; path is '%v'.", cmdPath)
+	}
+}
+
+func TestHelpCmdComplete(t *testing.T) {
+	cmd := createTopicsTestCmd()
+	candidates := cmd.Complete([]string{"help", "e"})
+	if !reflect.DeepEqual(candidates, []string{"environment"}) {
+		t.Errorf("Completions are '%v'; expecting '[environment]'.", candidates)
+	}
+
+	candidates = cmd.Complete([]string{"help", "serve", ""})
+	if candidates != nil {
+		t.Errorf("Completions are '%v'; expecting none.", candidates)
+	}
+}
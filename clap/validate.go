@@ -0,0 +1,185 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// registerArgName maps |name| to |arg| in the named arg map of |cmd|. If
+// |name| is already used by another named arg, the existing mapping is kept
+// and the conflict is recorded to be reported by Validate, Parse and
+// ParseArgs.
+func (cmd *Cmd) registerArgName(name string, arg *NamedArg) {
+	existing, exists := cmd.namedArgMap[name]
+	if exists {
+		cmd.defErrors = append(cmd.defErrors, fmt.Errorf(
+			"Name '%s' of argument '%s' of command '%s' is already used by " +
+			"argument '%s'.", name, arg.name, cmd.name, existing.name))
+		return
+	}
+
+	cmd.namedArgMap[name] = arg
+}
+
+// Validate checks the definitions of |cmd| and all its descendant commands,
+// and returns an error listing all the problems found:
+//     - Named args whose names, short names or aliases conflict with those
+//       of other named args of the same command.
+//     - Named args which conflict with persistent args of ancestors.
+//     - Named args whose names are also names of sub-commands.
+//     - Sub-command aliases which conflict with other sub-commands.
+//     - Short names which are longer than one character in GNU mode.
+//     - Duplicate positional argument names.
+//     - More than one variadic positional argument, and variadic positional
+//       arguments whose values do not accumulate.
+//     - Commands, named args and positional args without help text.
+// Validate is meant to be called from a test, or once at startup. Parse and
+// ParseArgs report only the conflicting names, invalid map arguments and
+// invalid variadic positional arguments.
+func (cmd *Cmd) Validate() error {
+	var problems []error
+	for _, entry := range cmd.allCmdPaths() {
+		problems = append(problems, entry.cmd.validate(entry.path)...)
+	}
+
+	return cmd.definitionError(problems)
+}
+
+// checkDefinition returns an error listing the problems recorded while
+// registering the arguments and sub-commands of |cmd| and its descendants,
+// or nil if there are none.
+func (cmd *Cmd) checkDefinition() error {
+	var problems []error
+	for _, entry := range cmd.allCmdPaths() {
+		problems = append(problems, entry.cmd.defErrors...)
+	}
+
+	return cmd.definitionError(problems)
+}
+
+func (cmd *Cmd) definitionError(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+
+	return fmt.Errorf(
+		"Invalid definition of command '%s'.\n%s", cmd.name, strings.Join(messages, "\n"))
+}
+
+// allCmdPaths lists |cmd| and all its descendant commands, including hidden
+// ones, in depth first order.
+func (cmd *Cmd) allCmdPaths() []cmdPathEntry {
+	var entries []cmdPathEntry
+	var walk func(path string, c *Cmd)
+	walk = func(path string, c *Cmd) {
+		entries = append(entries, cmdPathEntry{path, c})
+		var names []string
+		for name, subCmd := range c.subCmds {
+			if name == subCmd.name {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walk(path + " " + name, c.subCmds[name])
+		}
+	}
+	walk(cmd.name, cmd)
+
+	return entries
+}
+
+// validate returns the problems in the definition of |cmd|, which is invoked
+// as |path|.
+func (cmd *Cmd) validate(path string) []error {
+	problems := append([]error(nil), cmd.defErrors...)
+
+	if len(strings.TrimSpace(cmd.description)) == 0 {
+		problems = append(problems, fmt.Errorf("Command '%s' has no description.", path))
+	}
+
+	for _, arg := range cmd.namedArgList {
+		if len(strings.TrimSpace(arg.help)) == 0 {
+			problems = append(problems, fmt.Errorf(
+				"Argument '%s' of command '%s' has no help text.", arg.name, path))
+		}
+
+		if cmd.isGNUMode() && len(arg.short) > 1 {
+			problems = append(problems, fmt.Errorf(
+				"Short name '%s' of argument '%s' of command '%s' is longer than " +
+				"one character.", arg.short, arg.name, path))
+		}
+
+		for _, name := range append([]string{arg.name, arg.short}, arg.aliases...) {
+			if len(name) == 0 {
+				continue
+			}
+
+			subCmd, exists := cmd.subCmds[name]
+			if exists {
+				problems = append(problems, fmt.Errorf(
+					"Name '%s' of argument '%s' of command '%s' is also the name of " +
+					"sub-command '%s'.", name, arg.name, path, subCmd.name))
+			}
+
+			for ancestor := cmd.parent; ancestor != nil; ancestor = ancestor.parent {
+				inherited, exists := ancestor.namedArgMap[name]
+				if exists && inherited.persistent {
+					problems = append(problems, fmt.Errorf(
+						"Name '%s' of argument '%s' of command '%s' is already used by " +
+						"persistent argument '%s' of command '%s'.",
+						name, arg.name, path, inherited.name, ancestor.name))
+				}
+			}
+		}
+	}
+
+	positionalNames := make(map[string]bool)
+	for _, positional := range cmd.positionals {
+		if positionalNames[positional.name] {
+			problems = append(problems, fmt.Errorf(
+				"Positional argument '%s' of command '%s' is declared more than once.",
+				positional.name, path))
+		}
+		positionalNames[positional.name] = true
+
+		if len(strings.TrimSpace(positional.help)) == 0 {
+			problems = append(problems, fmt.Errorf(
+				"Positional argument '%s' of command '%s' has no help text.",
+				positional.name, path))
+		}
+	}
+
+	for _, topic := range cmd.helpTopics {
+		subCmd, exists := cmd.subCmds[topic.name]
+		if exists {
+			problems = append(problems, fmt.Errorf(
+				"Help topic '%s' of command '%s' is also the name of sub-command '%s'.",
+				topic.name, path, subCmd.name))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,104 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	var verbose, quiet, hidden bool
+	var name string
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddBoolArg("verbose", "v", &verbose, false, false, "Verbose.").SetPersistent()
+	subCmd := NewCmd("sub", "A sub-command.")
+	subCmd.AddStringArg("name", "n", &name, "", false, "Name.")
+	subCmd.AddStringPositional("file", &name, "File.")
+	cmd.AddSubCmd(subCmd)
+
+	err := cmd.Validate()
+	if err != nil {
+		t.Errorf("Unexpected validation error.\n%s", err.Error())
+	}
+
+	cmd.AddBoolArg("quiet", "h", &quiet, false, false, "Quiet.")
+	cmd.AddBoolArg("sub", "", &hidden, false, false, "")
+	subCmd.AddBoolArg("verbose", "", &verbose, false, false, "Verbose.")
+	subCmd.AddStringPositional("file", &name, "")
+	err = cmd.Validate()
+	if err == nil {
+		t.Errorf("Expecting validation errors.")
+		return
+	}
+
+	expected := []string{
+		"Name 'h' of argument 'quiet' of command 'tool' is already used by argument 'help'.",
+		"Argument 'sub' of command 'tool' has no help text.",
+		"Name 'sub' of argument 'sub' of command 'tool' is also the name of sub-command 'sub'.",
+		"Name 'verbose' of argument 'verbose' of command 'tool sub' is already used by " +
+			"persistent argument 'verbose' of command 'tool'.",
+		"Positional argument 'file' of command 'tool sub' is declared more than once.",
+		"Positional argument 'file' of command 'tool sub' has no help text.",
+	}
+	for _, problem := range expected {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Validation error does not report '%s':\n%s", problem, err.Error())
+		}
+	}
+
+	_, err = cmd.Parse([]string{"-h"})
+	if err == nil || !strings.Contains(err.Error(), expected[0]) {
+		t.Errorf("Parse does not report the conflicting short name 'h'; got '%v'.", err)
+	}
+	_, err = cmd.ParseArgs([]string{"-h"})
+	if err == nil || !strings.Contains(err.Error(), expected[0]) {
+		t.Errorf("ParseArgs does not report the conflicting short name 'h'; got '%v'.", err)
+	}
+	if cmd.namedArgMap["h"] != cmd.helpArg {
+		t.Errorf("Built-in short name 'h' was shadowed.")
+	}
+}
+
+func TestValidateEmptyShortName(t *testing.T) {
+	var a, b int
+	cmd := NewCmd("tool", "A tool.")
+	cmd.AddIntArg("alpha", "", &a, 0, false, "Alpha.")
+	cmd.AddIntArg("beta", "", &b, 0, false, "Beta.")
+	err := cmd.Validate()
+	if err != nil {
+		t.Errorf("Unexpected validation error for empty short names.\n%s", err.Error())
+	}
+	_, exists := cmd.namedArgMap[""]
+	if exists {
+		t.Errorf("Empty short name registered in the named arg map.")
+	}
+}
+
+func TestValidateGNUShortNameInSubCmd(t *testing.T) {
+	var b bool
+	cmd := NewCmd("tool", "A tool.")
+	cmd.EnableGNUMode()
+	subCmd := NewCmd("sub", "A sub-command.")
+	subCmd.AddBoolArg("bee", "bb", &b, false, false, "Bee.")
+	cmd.AddSubCmd(subCmd)
+
+	err := cmd.Validate()
+	if err == nil || !strings.Contains(err.Error(), "Short name 'bb' of argument 'bee'") {
+		t.Errorf("Expecting a validation error for short name 'bb'; got '%v'.", err)
+	}
+}
@@ -0,0 +1,300 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Value is the interface to the value of a named argument. Types
+// implementing Value can be registered as arguments with Cmd.AddValueArg.
+//
+// The string returned by String when the argument is registered is the
+// default value of the argument. Set should accept it, as it is used to
+// reset the argument to its default value.
+type Value interface {
+	// Set parses |s| and stores the parsed value.
+	Set(s string) error
+
+	// String returns the current value formatted as a string.
+	String() string
+}
+
+// BoolValue is an optional interface implemented by values of arguments
+// which need not be given an explicit value on the command line. If
+// IsBoolFlag returns true, then '-name' is equivalent to '-name=true'.
+type BoolValue interface {
+	Value
+	IsBoolFlag() bool
+}
+
+// repeatedValue is implemented by values which accumulate the values of
+// multiple occurrences of an argument.
+type repeatedValue interface {
+	Value
+
+	// reset restores the default value such that the next call to Set
+	// replaces it instead of adding to it.
+	reset()
+}
+
+// ClonableValue is an optional interface implemented by values which can
+// create a new value of the same kind with a destination of their own.
+// ParseArgs parses arguments into such new values, so that the values
+// registered with the command are not modified. The new value need not have
+// the current value of the original; it is reset to the default value of the
+// argument before use. ParseArgs returns an error for commands with values
+// which do not implement ClonableValue.
+type ClonableValue interface {
+	Value
+	Clone() Value
+}
+
+func isBoolValue(value Value) bool {
+	boolValue, ok := value.(BoolValue)
+	return ok && boolValue.IsBoolFlag()
+}
+
+func isRepeatedValue(value Value) bool {
+	_, ok := value.(repeatedValue)
+	return ok
+}
+
+type intValue int
+
+func newIntValue(p *int) *intValue {
+	return (*intValue)(p)
+}
+
+func (v *intValue) Set(s string) error {
+	int64Val, err := strconv.ParseInt(s, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	*v = intValue(int64Val)
+	return nil
+}
+
+func (v *intValue) String() string {
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func (v *intValue) Clone() Value {
+	return newIntValue(new(int))
+}
+
+type int64Value int64
+
+func newInt64Value(p *int64) *int64Value {
+	return (*int64Value)(p)
+}
+
+func (v *int64Value) Set(s string) error {
+	int64Val, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+
+	*v = int64Value(int64Val)
+	return nil
+}
+
+func (v *int64Value) String() string {
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func (v *int64Value) Clone() Value {
+	return newInt64Value(new(int64))
+}
+
+type uintValue uint
+
+func newUIntValue(p *uint) *uintValue {
+	return (*uintValue)(p)
+}
+
+func (v *uintValue) Set(s string) error {
+	uint64Val, err := strconv.ParseUint(s, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	*v = uintValue(uint64Val)
+	return nil
+}
+
+func (v *uintValue) String() string {
+	return strconv.FormatUint(uint64(*v), 10)
+}
+
+func (v *uintValue) Clone() Value {
+	return newUIntValue(new(uint))
+}
+
+type uint64Value uint64
+
+func newUInt64Value(p *uint64) *uint64Value {
+	return (*uint64Value)(p)
+}
+
+func (v *uint64Value) Set(s string) error {
+	uint64Val, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return err
+	}
+
+	*v = uint64Value(uint64Val)
+	return nil
+}
+
+func (v *uint64Value) String() string {
+	return strconv.FormatUint(uint64(*v), 10)
+}
+
+func (v *uint64Value) Clone() Value {
+	return newUInt64Value(new(uint64))
+}
+
+type float64Value float64
+
+func newFloat64Value(p *float64) *float64Value {
+	return (*float64Value)(p)
+}
+
+func (v *float64Value) Set(s string) error {
+	float64Val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	*v = float64Value(float64Val)
+	return nil
+}
+
+func (v *float64Value) String() string {
+	return strconv.FormatFloat(float64(*v), 'g', -1, 64)
+}
+
+func (v *float64Value) Clone() Value {
+	return newFloat64Value(new(float64))
+}
+
+type boolValue bool
+
+func newBoolValue(p *bool) *boolValue {
+	return (*boolValue)(p)
+}
+
+func (v *boolValue) Set(s string) error {
+	val, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+
+	*v = boolValue(val)
+	return nil
+}
+
+func (v *boolValue) String() string {
+	return strconv.FormatBool(bool(*v))
+}
+
+func (v *boolValue) Clone() Value {
+	return newBoolValue(new(bool))
+}
+
+func (v *boolValue) IsBoolFlag() bool {
+	return true
+}
+
+type stringValue string
+
+func newStringValue(p *string) *stringValue {
+	return (*stringValue)(p)
+}
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(s)
+	return nil
+}
+
+func (v *stringValue) String() string {
+	return string(*v)
+}
+
+func (v *stringValue) Clone() Value {
+	return newStringValue(new(string))
+}
+
+// sliceValue is the value of a slice argument. |dest| is a pointer to a
+// slice of one of the types supported by appendSliceDest. The slice pointed
+// to by |dest| when the value is created is its default value.
+type sliceValue struct {
+	dest interface{}
+
+	// The default elements of the slice.
+	def []string
+
+	// Indicates whether Set splits its argument into comma separated
+	// elements.
+	split bool
+
+	// Indicates whether |dest| holds values set after the last reset.
+	appended bool
+}
+
+func newSliceValue(dest interface{}, split bool) *sliceValue {
+	v := new(sliceValue)
+	v.dest = dest
+	v.def = sliceDestElems(dest)
+	v.split = split
+	v.appended = false
+
+	return v
+}
+
+func (v *sliceValue) Set(s string) error {
+	elems := []string{s}
+	if v.split {
+		elems = splitSliceValue(s)
+	}
+
+	err := appendSliceDest(v.dest, elems, !v.appended)
+	v.appended = true
+	return err
+}
+
+func (v *sliceValue) String() string {
+	return formatSliceDest(v.dest)
+}
+
+func (v *sliceValue) Clone() Value {
+	clone := new(sliceValue)
+	clone.dest = reflect.New(reflect.TypeOf(v.dest).Elem()).Interface()
+	clone.def = v.def
+	clone.split = v.split
+	clone.appended = false
+
+	return clone
+}
+
+func (v *sliceValue) reset() {
+	appendSliceDest(v.dest, v.def, true)
+	v.appended = false
+}
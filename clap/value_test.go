@@ -0,0 +1,141 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package clap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// A user defined value which accepts only lower case strings.
+type lowerValue struct {
+	val string
+}
+
+func (v *lowerValue) Set(s string) error {
+	if strings.ToLower(s) != s {
+		return fmt.Errorf("'%s' is not lower case.", s)
+	}
+
+	v.val = s
+	return nil
+}
+
+func (v *lowerValue) String() string {
+	return v.val
+}
+
+func (v *lowerValue) Clone() Value {
+	return new(lowerValue)
+}
+
+// A user defined value which behaves like a bool flag.
+type onOffValue struct {
+	on bool
+}
+
+func (v *onOffValue) Set(s string) error {
+	switch s {
+	case "on", "true":
+		v.on = true
+	case "off", "false":
+		v.on = false
+	default:
+		return fmt.Errorf("Expecting 'on' or 'off'; got '%s'.", s)
+	}
+
+	return nil
+}
+
+func (v *onOffValue) String() string {
+	if v.on {
+		return "on"
+	}
+
+	return "off"
+}
+
+func (v *onOffValue) IsBoolFlag() bool {
+	return true
+}
+
+func TestValueArg(t *testing.T) {
+	lower := &lowerValue{"default"}
+	onOff := new(onOffValue)
+	cmd := NewCmd("command", "A test command.")
+	cmd.AddValueArg("lower", "l", lower, false, "A lower case argument.")
+	cmd.AddValueArg("switch", "s", onOff, false, "An on/off argument.")
+
+	_, err := cmd.Parse([]string{"-l", "hello", "-s", "positional"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if lower.val != "hello" {
+		t.Errorf("Argument 'lower' has value '%s'; expecting '%s'.", lower.val, "hello")
+	}
+	if !onOff.on {
+		t.Errorf("Argument 'switch' is not on.")
+	}
+	if len(cmd.Args()) != 1 || cmd.Args()[0] != "positional" {
+		t.Errorf("Expecting a single positional argument. Found '%v'.", cmd.Args())
+	}
+
+	err = cmd.Clear()
+	if err != nil {
+		t.Errorf("Error clearing arg set.\n%s", err.Error())
+		return
+	}
+
+	if lower.val != "default" || onOff.on {
+		t.Errorf("Value arguments not reset after clearing.")
+	}
+
+	_, err = cmd.Parse([]string{"-l", "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "'lower'") {
+		t.Errorf("Expecting an error naming argument 'lower'. Got '%v'.", err)
+	}
+}
+
+func TestBindValue(t *testing.T) {
+	type options struct {
+		Lower lowerValue `name:"lower" default:"abc" help:"A lower case argument."`
+		Switch *onOffValue `name:"switch" help:"An on/off argument."`
+	}
+	opts := new(options)
+	cmd, err := NewCmdFromStruct("command", "A test command.", opts)
+	if err != nil {
+		t.Errorf("Error while binding:\n%s", err.Error())
+		return
+	}
+
+	if opts.Lower.val != "abc" {
+		t.Errorf("Argument 'lower' has default '%s'; expecting '%s'.", opts.Lower.val, "abc")
+	}
+
+	_, err = cmd.Parse([]string{"--switch"})
+	if err != nil {
+		t.Errorf("Error while parsing:\n%s", err.Error())
+		return
+	}
+
+	if !opts.Switch.on {
+		t.Errorf("Argument 'switch' is not on.")
+	}
+}